@@ -0,0 +1,18 @@
+package gomegamatchers
+
+import (
+	"os"
+
+	"github.com/onsi/ginkgo/config"
+)
+
+// ColorFailures colors the failure messages of the Helpfully matchers with
+// ANSI escape codes: paths in bold, expected values in green and actual
+// values in red, with the region in which long strings differ highlighted.
+// Colors are left out when Ginkgo runs with -noColor, as read from
+// github.com/onsi/ginkgo/config, or the NO_COLOR environment variable is set.
+var ColorFailures = false
+
+func colorFailures() bool {
+	return ColorFailures && !config.DefaultReporterConfig.NoColor && os.Getenv("NO_COLOR") == ""
+}
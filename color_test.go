@@ -0,0 +1,62 @@
+package gomegamatchers_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/config"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+var _ = Describe("ColorFailures", func() {
+	var (
+		noColor       string
+		hasNoColor    bool
+		ginkgoNoColor bool
+	)
+
+	BeforeEach(func() {
+		noColor, hasNoColor = os.LookupEnv("NO_COLOR")
+		Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+		ginkgoNoColor = config.DefaultReporterConfig.NoColor
+		config.DefaultReporterConfig.NoColor = false
+		gomegamatchers.ColorFailures = true
+	})
+
+	AfterEach(func() {
+		gomegamatchers.ColorFailures = false
+		config.DefaultReporterConfig.NoColor = ginkgoNoColor
+		if hasNoColor {
+			Expect(os.Setenv("NO_COLOR", noColor)).To(Succeed())
+		}
+	})
+
+	It("colors the failure message", func() {
+		message := gomegamatchers.HelpfullyMatchYAML("port: 8080").FailureMessage("port: 8081")
+		Expect(message).To(ContainSubstring("error at \x1b[1m[port]\x1b[0m:"))
+		Expect(message).To(ContainSubstring("\x1b[31m<int> 8081\x1b[0m"))
+		Expect(message).To(ContainSubstring("\x1b[32m<int> 8080\x1b[0m"))
+	})
+
+	It("highlights where long strings differ", func() {
+		message := gomegamatchers.HelpfullyMatchJSON(`{"url": "https://example.com/releases/v1.2.3/download.tgz"}`).
+			FailureMessage(`{"url": "https://example.com/releases/v1.2.4/download.tgz"}`)
+		Expect(message).To(ContainSubstring("v1.2.\x1b[7m4\x1b[27m/download.tgz"))
+	})
+
+	It("leaves colors out when NO_COLOR is set", func() {
+		Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+		defer os.Unsetenv("NO_COLOR")
+
+		message := gomegamatchers.HelpfullyMatchYAML("port: 8080").FailureMessage("port: 8081")
+		Expect(message).NotTo(ContainSubstring("\x1b["))
+	})
+
+	It("leaves colors out when Ginkgo runs with -noColor", func() {
+		config.DefaultReporterConfig.NoColor = true
+
+		message := gomegamatchers.HelpfullyMatchYAML("port: 8080").FailureMessage("port: 8081")
+		Expect(message).NotTo(ContainSubstring("\x1b["))
+	})
+})
@@ -0,0 +1,103 @@
+package deepdiff
+
+import (
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// Options configures Compare. The zero value compares values exactly and
+// collects every difference. Paths are written the way failure messages
+// print them, such as "[instance_groups][*][jobs]", where "*" matches any
+// map key or slice index.
+type Options struct {
+	// MaxDifferences stops the comparison once this many differences have
+	// been found. Zero means every difference is collected.
+	MaxDifferences int
+
+	// IgnoreExtraKeys treats expected maps as a subset of actual maps.
+	IgnoreExtraKeys bool
+
+	// IgnoreOrder compares every slice regardless of the order of its
+	// elements. IgnoreOrderPaths does so only for the selected slices.
+	IgnoreOrder      bool
+	IgnoreOrderPaths []string
+
+	// SliceKeys maps paths such as "instance_groups[*]" to the field that
+	// identifies each element of the selected slices, such as "name".
+	SliceKeys map[string]string
+
+	// IgnorePaths skips the selected values entirely. AnyValuePaths only
+	// requires the selected map keys to be present on both sides.
+	IgnorePaths   []string
+	AnyValuePaths []string
+
+	// MaxDepth stops walking values nested more than this many path
+	// segments deep, and reports differing values there as DepthExceeded.
+	// Zero means no limit.
+	MaxDepth int
+
+	// NumericValues compares numbers by value whatever their types. Floats
+	// may also differ by up to AbsoluteTolerance, or by up to
+	// RelativeTolerance times the larger of the two. Setting either
+	// tolerance implies NumericValues.
+	NumericValues     bool
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+
+	// LooseScalars treats a string and a boolean or number as equal when
+	// they have the same canonical string form, such as 1.0 and "1.0".
+	// Each loosely equal pair is passed to OnLooseMatch, when it is set, as
+	// a Difference of Kind LooseMatch rather than returned by Compare.
+	LooseScalars bool
+	OnLooseMatch func(Difference)
+
+	// FieldTag names struct fields by a tag such as "yaml" or "json", so
+	// that they appear in paths, and are selected by patterns, as the keys
+	// they are encoded with. Without it, fields appear as Fields.
+	FieldTag string
+}
+
+// Compare returns the differences between expected and actual. It returns
+// an error when a path in options cannot be parsed.
+func Compare(expected interface{}, actual interface{}, options Options) ([]Difference, error) {
+	internalOptions, err := options.internal()
+	if err != nil {
+		return nil, err
+	}
+
+	var differences []Difference
+	for _, nested := range deepequal.CompareAll(expected, actual, internalOptions) {
+		differences = append(differences, newDifference(nested))
+	}
+
+	return differences, nil
+}
+
+func (options Options) internal() (deepequal.Options, error) {
+	var onLooseMatch func(diff.Difference)
+	if options.OnLooseMatch != nil {
+		onLooseMatch = func(looseMatch diff.Difference) {
+			options.OnLooseMatch(newDifference(looseMatch))
+		}
+	}
+
+	return deepequal.Options{
+		MaxDifferences:  options.MaxDifferences,
+		IgnoreExtraKeys: options.IgnoreExtraKeys,
+		IgnoreOrder:     options.IgnoreOrder,
+		MaxDepth:        options.MaxDepth,
+		FieldTag:        options.FieldTag,
+
+		NumericValues:     options.NumericValues,
+		AbsoluteTolerance: options.AbsoluteTolerance,
+		RelativeTolerance: options.RelativeTolerance,
+
+		LooseScalars: options.LooseScalars,
+		OnLooseMatch: onLooseMatch,
+	}.WithPaths(deepequal.PathOptions{
+		IgnoreOrderPaths: options.IgnoreOrderPaths,
+		SliceKeys:        options.SliceKeys,
+		IgnorePaths:      options.IgnorePaths,
+		AnyValuePaths:    options.AnyValuePaths,
+	})
+}
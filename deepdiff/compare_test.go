@@ -0,0 +1,146 @@
+package deepdiff_test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/deepdiff"
+)
+
+var _ = Describe("Compare", func() {
+	It("returns no differences when the values match", func() {
+		value := map[string]interface{}{"a": []interface{}{1, 2}}
+
+		differences, err := deepdiff.Compare(value, value, deepdiff.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+	})
+
+	It("returns every difference with its path, kind and values", func() {
+		expected := map[string]interface{}{
+			"name":  "cf",
+			"port":  80,
+			"hosts": []interface{}{"a", "b"},
+			"tags":  []interface{}{"x"},
+		}
+		actual := map[string]interface{}{
+			"name":    "diego",
+			"port":    "80",
+			"hosts":   []interface{}{"a"},
+			"tags":    []interface{}{"x", "y"},
+			"version": 2,
+		}
+
+		differences, err := deepdiff.Compare(expected, actual, deepdiff.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(HaveLen(5))
+
+		Expect(differences[0].Path()).To(Equal(deepdiff.Path{"hosts"}))
+		Expect(differences[0].Kind()).To(Equal(deepdiff.MissingElements))
+		Expect(differences[0].Expected()).To(Equal([]interface{}{"b"}))
+		Expect(differences[0].Actual()).To(BeNil())
+
+		Expect(differences[1].Path()).To(Equal(deepdiff.Path{"name"}))
+		Expect(differences[1].Kind()).To(Equal(deepdiff.ValueMismatch))
+		Expect(differences[1].Expected()).To(Equal("cf"))
+		Expect(differences[1].Actual()).To(Equal("diego"))
+
+		Expect(differences[2].Path()).To(Equal(deepdiff.Path{"port"}))
+		Expect(differences[2].Kind()).To(Equal(deepdiff.TypeMismatch))
+		Expect(differences[2].Expected()).To(Equal(reflect.TypeOf(80)))
+		Expect(differences[2].Actual()).To(Equal("80"))
+
+		Expect(differences[3].Path()).To(Equal(deepdiff.Path{"tags"}))
+		Expect(differences[3].Kind()).To(Equal(deepdiff.ExtraElements))
+		Expect(differences[3].Actual()).To(Equal([]interface{}{"y"}))
+
+		Expect(differences[4].Path()).To(BeEmpty())
+		Expect(differences[4].Kind()).To(Equal(deepdiff.ExtraKey))
+		Expect(differences[4].Actual()).To(Equal("version"))
+	})
+
+	It("applies the options", func() {
+		expected := map[string]interface{}{
+			"jobs": []interface{}{
+				map[string]interface{}{"name": "router", "uuid": "1"},
+				map[string]interface{}{"name": "api", "uuid": "2"},
+			},
+		}
+		actual := map[string]interface{}{
+			"jobs": []interface{}{
+				map[string]interface{}{"name": "api", "uuid": "3"},
+				map[string]interface{}{"name": "router", "uuid": "1", "port": 80},
+			},
+		}
+
+		differences, err := deepdiff.Compare(expected, actual, deepdiff.Options{
+			SliceKeys:       map[string]string{"jobs[*]": "name"},
+			IgnoreExtraKeys: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Path()).To(Equal(deepdiff.Path{
+			"jobs",
+			deepdiff.ElementKey{Field: "name", Value: "api"},
+			"uuid",
+		}))
+		Expect(differences[0].Path().String()).To(Equal("[jobs][name=api][uuid]"))
+
+		differences, err = deepdiff.Compare(expected, actual, deepdiff.Options{
+			SliceKeys:   map[string]string{"jobs[*]": "name"},
+			IgnorePaths: []string{"[jobs][*][uuid]", "[jobs][*][port]"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+	})
+
+	It("reports failing embedded matchers", func() {
+		differences, err := deepdiff.Compare(map[string]interface{}{"a": HaveLen(3)}, map[string]interface{}{"a": "ab"}, deepdiff.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Kind()).To(Equal(deepdiff.MatcherFailure))
+		Expect(differences[0].Actual()).To(ContainSubstring("to have length 3"))
+	})
+
+	It("reports struct fields as Field segments", func() {
+		type image struct{ Name, Tag string }
+
+		differences, err := deepdiff.Compare(&image{Name: "nginx", Tag: "1.19"}, &image{Name: "nginx", Tag: "latest"}, deepdiff.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Path()).To(Equal(deepdiff.Path{deepdiff.Field("Tag")}))
+		Expect(differences[0].Path().String()).To(Equal(".Tag"))
+	})
+
+	It("names struct fields by FieldTag", func() {
+		type image struct {
+			Tag string `yaml:"tag"`
+		}
+
+		differences, err := deepdiff.Compare(image{Tag: "1.19"}, image{Tag: "latest"}, deepdiff.Options{FieldTag: "yaml"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Path()).To(Equal(deepdiff.Path{"tag"}))
+	})
+
+	It("passes loose matches to OnLooseMatch", func() {
+		var looseMatches []deepdiff.Difference
+		differences, err := deepdiff.Compare(map[string]interface{}{"port": "8080"}, map[string]interface{}{"port": 8080}, deepdiff.Options{
+			LooseScalars: true,
+			OnLooseMatch: func(looseMatch deepdiff.Difference) {
+				looseMatches = append(looseMatches, looseMatch)
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+		Expect(looseMatches).To(HaveLen(1))
+		Expect(looseMatches[0].Path()).To(Equal(deepdiff.Path{"port"}))
+		Expect(looseMatches[0].Kind()).To(Equal(deepdiff.LooseMatch))
+	})
+
+	It("returns an error when a path cannot be parsed", func() {
+		_, err := deepdiff.Compare(1, 1, deepdiff.Options{IgnorePaths: []string{"[a"}})
+		Expect(err).To(MatchError(`invalid path pattern "[a": missing ']'`))
+	})
+})
@@ -0,0 +1,156 @@
+package deepdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// Difference is a single mismatch between the expected and actual values.
+type Difference interface {
+	// Path leads from the root of the compared values to the mismatch.
+	Path() Path
+
+	// Kind tells what sort of mismatch this is.
+	Kind() Kind
+
+	// Expected and Actual return the values involved in the mismatch. Which
+	// values they are depends on the Kind, and either may be nil.
+	Expected() interface{}
+	Actual() interface{}
+}
+
+// Kind names a sort of Difference.
+type Kind string
+
+const (
+	// ValueMismatch: Expected and Actual are the two values.
+	ValueMismatch Kind = "value mismatch"
+
+	// TypeMismatch: Expected is the expected reflect.Type and Actual the
+	// actual value.
+	TypeMismatch Kind = "type mismatch"
+
+	// MissingKey and ExtraKey: Expected or Actual is the map key.
+	MissingKey Kind = "missing key"
+	ExtraKey   Kind = "extra key"
+
+	// MissingElements and ExtraElements: Expected or Actual is a slice of
+	// the elements past the end of the shorter slice.
+	MissingElements Kind = "missing elements"
+	ExtraElements   Kind = "extra elements"
+
+	// UnmatchedElements is reported for slices compared regardless of
+	// order: Expected and Actual are slices of the elements left unpaired.
+	UnmatchedElements Kind = "unmatched elements"
+
+	// MissingElement and ExtraElement are reported for slices keyed by a
+	// field: Expected or Actual is the ElementKey of the element.
+	MissingElement Kind = "missing element"
+	ExtraElement   Kind = "extra element"
+
+	// MatcherFailure is reported when a gomega matcher in the expected value
+	// rejects the actual value: Actual is its failure message or error.
+	MatcherFailure Kind = "matcher failure"
+
+	// CycleDetected is reported when one side refers back to a value that
+	// encloses it where the other side is nil or empty: Expected and Actual
+	// are the two values. Cycles on both sides are compared as
+	// reflect.DeepEqual compares them.
+	CycleDetected Kind = "cycle detected"
+
+	// DepthExceeded is reported for values that differ below
+	// Options.MaxDepth: Expected and Actual are the values at that depth.
+	DepthExceeded Kind = "depth exceeded"
+
+	// LooseMatch is only passed to Options.OnLooseMatch, for scalars that
+	// are equal by their string form alone: Expected and Actual are the two
+	// values.
+	LooseMatch Kind = "loose match"
+)
+
+// Path is the list of map keys, slice indexes, Fields and ElementKeys that
+// lead to a value.
+type Path []interface{}
+
+func (path Path) String() string {
+	var segments []string
+	for _, segment := range path {
+		if field, ok := segment.(Field); ok {
+			segments = append(segments, "."+string(field))
+			continue
+		}
+
+		segments = append(segments, fmt.Sprintf("[%+v]", segment))
+	}
+
+	return strings.Join(segments, "")
+}
+
+// Field is the path segment of a struct field, and prints as .Name.
+type Field string
+
+// ElementKey identifies an element of a slice keyed by one of its fields,
+// and prints as field=value.
+type ElementKey struct {
+	Field interface{}
+	Value interface{}
+}
+
+func (key ElementKey) String() string {
+	return fmt.Sprintf("%+v=%+v", key.Field, key.Value)
+}
+
+type difference struct {
+	nested diff.Difference
+
+	path     Path
+	kind     Kind
+	expected interface{}
+	actual   interface{}
+}
+
+func newDifference(nested diff.Difference) *difference {
+	var path Path
+	for _, segment := range nested.Path() {
+		path = append(path, publicKey(segment))
+	}
+
+	return &difference{
+		nested:   nested,
+		path:     path,
+		kind:     Kind(nested.Kind()),
+		expected: publicKey(nested.Expected()),
+		actual:   publicKey(nested.Actual()),
+	}
+}
+
+// publicKey converts the element keys and field names of the internal
+// differences to ElementKeys and Fields, leaving other values alone.
+func publicKey(value interface{}) interface{} {
+	switch value := value.(type) {
+	case diff.ElementKey:
+		return ElementKey{Field: value.Field, Value: value.Value}
+	case diff.FieldName:
+		return Field(value)
+	default:
+		return value
+	}
+}
+
+func (d *difference) Path() Path {
+	return d.path
+}
+
+func (d *difference) Kind() Kind {
+	return d.kind
+}
+
+func (d *difference) Expected() interface{} {
+	return d.expected
+}
+
+func (d *difference) Actual() interface{} {
+	return d.actual
+}
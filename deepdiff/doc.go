@@ -0,0 +1,16 @@
+// Package deepdiff finds every structural difference between two values
+// and renders them the way the Helpfully matchers in gomegamatchers do, so
+// that other matchers can report localized failures of their own.
+//
+// Compare walks maps, slices, arrays, the exported fields of structs,
+// pointers and primitive values, and delegates to any
+// gomega matcher found in the expected value. Each Difference it returns
+// knows its Path, its Kind and the expected and actual values involved.
+// Render formats a list of differences as "error at [path]" blocks, and
+// MarshalReport encodes them as JSON for tools that display them.
+//
+// The exported API of this package is stable: identifiers will not be
+// removed or changed in incompatible ways. New Kinds, new Options fields
+// and new methods on the types defined here may be added, so switch
+// statements over Kind should have a default case.
+package deepdiff
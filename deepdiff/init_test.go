@@ -0,0 +1,13 @@
+package deepdiff_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeepDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "deepdiff")
+}
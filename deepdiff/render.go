@@ -0,0 +1,45 @@
+package deepdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
+)
+
+// Describer is implemented by differences that explain themselves. Render
+// prints the description of such a difference under its header, indented by
+// two spaces as the differences returned by Compare are.
+type Describer interface {
+	Description() string
+}
+
+// Render formats each difference under an "error at [path]" header, the
+// way the Helpfully matchers print their failure messages. Differences that
+// were not returned by Compare and do not implement Describer are printed
+// from their Kind, Expected and Actual values.
+func Render(differences []Difference) string {
+	var failures []string
+	for _, difference := range differences {
+		failures = append(failures, render(difference))
+	}
+
+	return strings.Join(failures, "\n\n")
+}
+
+func render(d Difference) string {
+	if d, ok := d.(*difference); ok {
+		return prettyprint.ExpectationFailure(d.nested)
+	}
+
+	if describer, ok := d.(Describer); ok {
+		return fmt.Sprintf("error at %s:\n%s", d.Path(), describer.Description())
+	}
+
+	return fmt.Sprintf(`error at %s:
+  %s:
+    Expected
+        <%T> %+v
+    to equal
+        <%T> %+v`, d.Path(), d.Kind(), d.Actual(), d.Actual(), d.Expected(), d.Expected())
+}
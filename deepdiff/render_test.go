@@ -0,0 +1,71 @@
+package deepdiff_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/deepdiff"
+)
+
+type customDifference struct{}
+
+func (customDifference) Path() deepdiff.Path {
+	return deepdiff.Path{"spec", 2, "image"}
+}
+
+func (customDifference) Kind() deepdiff.Kind {
+	return deepdiff.Kind("image mismatch")
+}
+
+func (customDifference) Expected() interface{} {
+	return "nginx:1.19"
+}
+
+func (customDifference) Actual() interface{} {
+	return "nginx:latest"
+}
+
+type describedDifference struct {
+	customDifference
+}
+
+func (describedDifference) Description() string {
+	return "  image mismatch:\n    nginx:latest is not pinned"
+}
+
+var _ = Describe("Render", func() {
+	It("renders differences from Compare like the Helpfully matchers", func() {
+		differences, err := deepdiff.Compare(
+			map[string]interface{}{"colors": []interface{}{"red", "blue"}},
+			map[string]interface{}{"colors": []interface{}{"red", "green"}},
+			deepdiff.Options{},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		failure := deepdiff.Render(differences)
+		Expect(failure).To(ContainSubstring("error at [colors][1]:"))
+		Expect(failure).To(ContainSubstring("  value mismatch:"))
+		Expect(failure).To(ContainSubstring("        <string> green"))
+		Expect(failure).To(ContainSubstring("        <string> blue"))
+	})
+
+	It("renders other implementations of Difference from their values", func() {
+		failure := deepdiff.Render([]deepdiff.Difference{customDifference{}})
+		Expect(failure).To(Equal(`error at [spec][2][image]:
+  image mismatch:
+    Expected
+        <string> nginx:latest
+    to equal
+        <string> nginx:1.19`))
+	})
+
+	It("renders the description of differences that implement Describer", func() {
+		failure := deepdiff.Render([]deepdiff.Difference{describedDifference{}})
+		Expect(failure).To(Equal(`error at [spec][2][image]:
+  image mismatch:
+    nginx:latest is not pinned`))
+	})
+
+	It("returns an empty string when there are no differences", func() {
+		Expect(deepdiff.Render(nil)).To(BeEmpty())
+	})
+})
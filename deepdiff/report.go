@@ -0,0 +1,41 @@
+package deepdiff
+
+import (
+	"encoding/json"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// MarshalReport encodes differences as a JSON object with a "differences"
+// array, for tools that display them. Each entry holds the "path" as an
+// array of segments, the "kind", and the "expected" and "actual" values.
+// Map keys and slice indexes are written as JSON values, Fields as
+// {"struct_field": "Name"} and ElementKeys as
+// {"element_key": {"field": "name", "value": "router"}}. Values that JSON
+// cannot represent, such as types, are written as they print.
+func MarshalReport(differences []Difference) ([]byte, error) {
+	report := diff.Report{Differences: []diff.ReportEntry{}}
+	for _, d := range differences {
+		var path []interface{}
+		for _, segment := range d.Path() {
+			path = append(path, internalKey(segment))
+		}
+
+		report.Differences = append(report.Differences,
+			diff.NewReportEntry(path, string(d.Kind()), internalKey(d.Expected()), internalKey(d.Actual())))
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// internalKey reverses publicKey.
+func internalKey(value interface{}) interface{} {
+	switch value := value.(type) {
+	case ElementKey:
+		return diff.ElementKey{Field: value.Field, Value: value.Value}
+	case Field:
+		return diff.FieldName(value)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,38 @@
+package deepdiff_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/deepdiff"
+)
+
+var _ = Describe("MarshalReport", func() {
+	It("encodes the differences from Compare as JSON", func() {
+		differences, err := deepdiff.Compare(
+			map[string]interface{}{"jobs": []interface{}{map[string]interface{}{"name": "router", "port": 443}}},
+			map[string]interface{}{"jobs": []interface{}{map[string]interface{}{"name": "router", "port": 80}}},
+			deepdiff.Options{SliceKeys: map[string]string{"[jobs]": "name"}},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := deepdiff.MarshalReport(differences)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(MatchJSON(`{"differences": [{
+			"path": ["jobs", {"element_key": {"field": "name", "value": "router"}}, "port"],
+			"kind": "value mismatch",
+			"expected": 443,
+			"actual": 80
+		}]}`))
+	})
+
+	It("encodes differences that were not returned by Compare", func() {
+		report, err := deepdiff.MarshalReport([]deepdiff.Difference{customDifference{}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(MatchJSON(`{"differences": [{
+			"path": ["spec", 2, "image"],
+			"kind": "image mismatch",
+			"expected": "nginx:1.19",
+			"actual": "nginx:latest"
+		}]}`))
+	})
+})
@@ -0,0 +1,60 @@
+package gomegamatchers
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+)
+
+// HelpfullyEqual succeeds when actual is deeply equal to expected, and lists
+// every difference the way HelpfullyMatchYAML does. Struct fields, pointers,
+// arrays and interfaces are walked, so failures point at paths such as
+// .Spec.Containers[2].Image.
+func HelpfullyEqual(expected interface{}) types.GomegaMatcher {
+	return &HelpfullyEqualMatcher{
+		Expected: expected,
+	}
+}
+
+type HelpfullyEqualMatcher struct {
+	Expected interface{}
+
+	// MaxDifferences caps the number of differences listed in the failure
+	// message. Zero lists every difference.
+	MaxDifferences int
+
+	// MaxDepth stops walking values nested more than this many fields,
+	// indexes and map keys deep, and reports differing values there as a
+	// whole. Zero means no limit. Values that refer back to themselves are
+	// detected whatever the depth.
+	MaxDepth int
+}
+
+func (matcher *HelpfullyEqualMatcher) Match(actual interface{}) (success bool, err error) {
+	if actual == nil && matcher.Expected == nil {
+		return false, fmt.Errorf("Refusing to compare <nil> to <nil>.\nBe explicit and use BeNil() instead.  This is to avoid mistakes where both sides of an assertion are erroneously uninitialized.")
+	}
+
+	equal, _ := compareDocuments(matcher.Expected, actual, matcher.options(), nil)
+
+	return equal, nil
+}
+
+func (matcher *HelpfullyEqualMatcher) FailureMessage(actual interface{}) (message string) {
+	_, message = compareDocuments(matcher.Expected, actual, matcher.options(), nil)
+
+	return message
+}
+
+func (matcher *HelpfullyEqualMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return format.Message(actual, "not to equal", matcher.Expected)
+}
+
+func (matcher *HelpfullyEqualMatcher) options() deepequal.Options {
+	return deepequal.Options{
+		MaxDifferences: matcher.MaxDifferences,
+		MaxDepth:       matcher.MaxDepth,
+	}
+}
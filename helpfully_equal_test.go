@@ -0,0 +1,147 @@
+package gomegamatchers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+type container struct {
+	Name  string
+	Image string
+}
+
+type podSpec struct {
+	Containers []container
+	Replicas   *int
+	Labels     map[string]string
+	Ports      [2]int
+	Owner      interface{}
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+type pod struct {
+	Name string
+	Spec *podSpec
+}
+
+var _ = Describe("HelpfullyEqualMatcher", func() {
+	var expected, actual pod
+
+	newPod := func() pod {
+		replicas := 3
+		return pod{
+			Name: "web",
+			Spec: &podSpec{
+				Containers: []container{
+					{Name: "app", Image: "app:1.0"},
+					{Name: "proxy", Image: "nginx:1.19"},
+				},
+				Replicas: &replicas,
+				Labels:   map[string]string{"tier": "frontend"},
+				Ports:    [2]int{80, 443},
+				Owner:    "team-a",
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		expected = newPod()
+		actual = newPod()
+	})
+
+	Describe("Match", func() {
+		It("returns true when the values are deeply equal", func() {
+			isMatch, err := gomegamatchers.HelpfullyEqual(expected).Match(actual)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isMatch).To(BeTrue())
+		})
+
+		It("returns false when a value behind a pointer differs", func() {
+			replicas := 5
+			actual.Spec.Replicas = &replicas
+
+			isMatch, err := gomegamatchers.HelpfullyEqual(expected).Match(actual)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isMatch).To(BeFalse())
+		})
+
+		It("returns an error when both values are nil", func() {
+			_, err := gomegamatchers.HelpfullyEqual(nil).Match(nil)
+			Expect(err).To(MatchError(ContainSubstring("Refusing to compare <nil> to <nil>")))
+		})
+	})
+
+	Describe("FailureMessage", func() {
+		It("reports the path of each differing struct field", func() {
+			actual.Spec.Containers[1].Image = "nginx:latest"
+			actual.Spec.Ports[1] = 8443
+
+			message := gomegamatchers.HelpfullyEqual(expected).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at .Spec.Containers[1].Image:"))
+			Expect(message).To(ContainSubstring("        <string> nginx:latest"))
+			Expect(message).To(ContainSubstring("error at .Spec.Ports[1]:"))
+			Expect(message).To(ContainSubstring("        <int> 8443"))
+		})
+
+		It("reports map keys and interfaces holding other types", func() {
+			actual.Spec.Labels["tier"] = "backend"
+			actual.Spec.Owner = 7
+
+			message := gomegamatchers.HelpfullyEqual(expected).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at .Spec.Labels[tier]:"))
+			Expect(message).To(ContainSubstring("error at .Spec.Owner:\n  type mismatch:"))
+		})
+
+		It("reports a nil pointer as a value mismatch", func() {
+			actual.Spec = nil
+
+			message := gomegamatchers.HelpfullyEqual(expected).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at .Spec:\n  value mismatch:"))
+		})
+
+		It("reports values that refer back to themselves on one side only", func() {
+			expected := &node{Name: "a"}
+			expected.Next = expected
+			actual := &node{Name: "a", Next: &node{Name: "a"}}
+
+			message := gomegamatchers.HelpfullyEqual(expected).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at .Next.Next:\n  cycle detected:"))
+		})
+
+		It("stops walking below MaxDepth", func() {
+			actual.Spec.Containers[1].Image = "nginx:latest"
+
+			message := (&gomegamatchers.HelpfullyEqualMatcher{
+				Expected: expected,
+				MaxDepth: 2,
+			}).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at .Spec.Containers:\n  depth exceeded:"))
+		})
+
+		It("limits the number of differences listed", func() {
+			actual.Name = "api"
+			actual.Spec.Labels["tier"] = "backend"
+
+			message := (&gomegamatchers.HelpfullyEqualMatcher{
+				Expected:       expected,
+				MaxDifferences: 1,
+			}).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at .Name:"))
+			Expect(message).NotTo(ContainSubstring("error at .Spec"))
+			Expect(message).To(ContainSubstring("stopped after 1 differences"))
+		})
+	})
+
+	Describe("NegatedFailureMessage", func() {
+		It("shows both values", func() {
+			message := gomegamatchers.HelpfullyEqual(expected).NegatedFailureMessage(actual)
+			Expect(message).To(ContainSubstring("not to equal"))
+			Expect(message).To(ContainSubstring("web"))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package gomegamatchers
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+)
+
+// File is a document on disk, for use as the expected or actual value of
+// HelpfullyMatchYAML, HelpfullyContainYAML and HelpfullyMatchJSON. Failure
+// messages name the file.
+type File struct {
+	Path string
+}
+
+// FromFile reads the document to compare from the file at path.
+func FromFile(path string) File {
+	return File{Path: path}
+}
+
+// documentInputs reads the Files and io.Readers given to a document
+// matcher. A reader can only be read once, but gomega passes the same
+// actual value to Match and then FailureMessage, so the contents last read
+// for each side are returned again for the same reader. Readers whose type
+// is not comparable cannot be told apart, and the last one read for a side
+// is taken to be the same reader.
+type documentInputs struct {
+	expected readerContents
+	actual   readerContents
+}
+
+// readerContents is the reader last read for one side, and what it held.
+type readerContents struct {
+	reader   io.Reader
+	contents string
+}
+
+func (inputs *documentInputs) readExpected(input interface{}) (interface{}, string, error) {
+	return read(input, &inputs.expected)
+}
+
+func (inputs *documentInputs) readActual(input interface{}) (interface{}, string, error) {
+	return read(input, &inputs.actual)
+}
+
+// read returns the contents of a File or io.Reader, along with the path of
+// the file, and returns any other input unchanged.
+func read(input interface{}, last *readerContents) (interface{}, string, error) {
+	if file, ok := input.(File); ok {
+		contents, err := ioutil.ReadFile(file.Path)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return string(contents), file.Path, nil
+	}
+
+	if _, ok := toString(input); ok {
+		return input, "", nil
+	}
+
+	reader, isReader := input.(io.Reader)
+	if !isReader {
+		return input, "", nil
+	}
+
+	if last.reader != nil && sameReader(last.reader, reader) {
+		return last.contents, "", nil
+	}
+
+	buf, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+
+	*last = readerContents{reader: reader, contents: string(buf)}
+
+	return last.contents, "", nil
+}
+
+func sameReader(last io.Reader, reader io.Reader) bool {
+	if reflect.TypeOf(last) != reflect.TypeOf(reader) {
+		return false
+	}
+
+	if !reflect.TypeOf(reader).Comparable() {
+		return true
+	}
+
+	return last == reader
+}
+
+// fixtureNames introduces a failure message with the files that were
+// compared, if any.
+func fixtureNames(expectedFile string, actualFile string) string {
+	var names []string
+	if actualFile != "" {
+		names = append(names, "actual "+actualFile)
+	}
+	if expectedFile != "" {
+		names = append(names, "expected "+expectedFile)
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("comparing %s\n\n", strings.Join(names, " to "))
+}
+
+// inFile names the file that an error about its contents came from.
+func inFile(path string, err error) error {
+	if path == "" {
+		return err
+	}
+
+	return fmt.Errorf("%s: %s", path, err)
+}
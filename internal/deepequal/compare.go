@@ -3,45 +3,228 @@ package deepequal
 import (
 	"reflect"
 
+	"github.com/onsi/gomega/types"
 	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
 )
 
+// Options configures how CompareAll walks the two values.
+type Options struct {
+	// MaxDifferences stops the comparison once this many differences have
+	// been found. Zero means every difference is collected.
+	MaxDifferences int
+
+	// IgnoreExtraKeys treats expected maps as a subset of actual maps: keys
+	// that are only present in actual are not reported.
+	IgnoreExtraKeys bool
+
+	// IgnoreOrder compares every slice as a multiset, so elements may appear
+	// in any order. IgnoreOrderPaths does the same for the selected slices.
+	IgnoreOrder      bool
+	IgnoreOrderPaths []Pattern
+
+	// SliceKeys aligns the elements of selected slices by an identity field
+	// rather than by position. It takes precedence over IgnoreOrder.
+	SliceKeys []SliceKey
+
+	// IgnorePaths skips the selected values entirely: they may differ, be
+	// missing or be extra. AnyValuePaths only requires the selected map
+	// keys to be present on both sides, whatever their values.
+	IgnorePaths   []Pattern
+	AnyValuePaths []Pattern
+
+	// MaxDepth stops walking values nested more than this many map keys,
+	// slice indexes and struct fields deep. Such values are compared with
+	// reflect.DeepEqual and reported as a whole. Zero means no limit.
+	MaxDepth int
+
+	// NumericValues compares integers and floats of any kind by their
+	// value, so that 8080, uint64(8080) and 8080.0 are equal. Floats may
+	// also differ by up to AbsoluteTolerance, or by up to RelativeTolerance
+	// times the larger magnitude. Setting either tolerance implies
+	// NumericValues.
+	NumericValues     bool
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+
+	// LooseScalars treats a string and a boolean or number as equal when
+	// they have the same canonical string form, such as 1.0 and "1.0", or
+	// true and "true". Scalars of the same kind, such as the strings "1.10"
+	// and "1.1", are compared as usual. Each loosely equal pair is passed
+	// to OnLooseMatch, when it is set, rather than reported as a
+	// difference.
+	LooseScalars bool
+	OnLooseMatch func(diff.Difference)
+
+	// FieldTag names struct fields by a tag such as "yaml", so that they are
+	// reported and selected by patterns the way the keys they are encoded
+	// with are. Without it, fields are reported as .Name.
+	FieldTag string
+}
+
 func Compare(expected interface{}, actual interface{}) (bool, diff.Difference) {
+	return first(newComparison(Options{MaxDifferences: 1}).compare(expected, actual, nil))
+}
+
+// CompareAll returns every difference between expected and actual, up to
+// options.MaxDifferences. An empty result means the values are equal.
+func CompareAll(expected interface{}, actual interface{}, options Options) []diff.Difference {
+	return newComparison(options).compare(expected, actual, nil)
+}
+
+type comparison struct {
+	options Options
+	found   int
+
+	expectedAncestors ancestors
+	actualAncestors   ancestors
+	visiting          map[visit]bool
+}
+
+func newComparison(options Options) *comparison {
+	return &comparison{
+		options:           options,
+		expectedAncestors: ancestors{},
+		actualAncestors:   ancestors{},
+		visiting:          map[visit]bool{},
+	}
+}
+
+func (c *comparison) done() bool {
+	return c.options.MaxDifferences > 0 && c.found >= c.options.MaxDifferences
+}
+
+func (c *comparison) difference(difference diff.Difference) []diff.Difference {
+	c.found++
+	return []diff.Difference{difference}
+}
+
+// equal reports whether expected and actual match under the same options,
+// without counting towards MaxDifferences. It returns the loose matches
+// found on the way rather than passing them to OnLooseMatch, since the
+// caller may not keep the pair.
+func (c *comparison) equal(expected interface{}, actual interface{}, path Path) (bool, []diff.Difference) {
+	var looseMatches []diff.Difference
+
+	options := c.options
+	options.MaxDifferences = 1
+	options.OnLooseMatch = func(looseMatch diff.Difference) {
+		looseMatches = append(looseMatches, looseMatch)
+	}
+
+	probe := newComparison(options)
+	probe.expectedAncestors = c.expectedAncestors
+	probe.actualAncestors = c.actualAncestors
+	probe.visiting = c.visiting
+
+	return len(probe.compare(expected, actual, path)) == 0, looseMatches
+}
+
+func (c *comparison) compare(expected interface{}, actual interface{}, path Path) []diff.Difference {
+	if c.ignored(path) || anyMatches(c.options.AnyValuePaths, path) {
+		return nil
+	}
+
+	if matcher, ok := expected.(types.GomegaMatcher); ok {
+		return c.matcher(matcher, actual)
+	}
+
 	expectedValue := reflect.ValueOf(expected)
 	actualValue := reflect.ValueOf(actual)
 
 	if expected == nil && actual == nil {
 		if expected == actual {
-			return true, diff.NoDifference{}
+			return nil
 		}
 	}
 
 	if !expectedValue.IsValid() {
-		return false, nil
+		return c.difference(diff.PrimitiveValueMismatch{
+			ExpectedValue: nil,
+			ActualValue:   actual,
+		})
 	}
 
 	if !actualValue.IsValid() {
-		return false, diff.PrimitiveTypeMismatch{
+		return c.difference(diff.PrimitiveTypeMismatch{
 			ExpectedType: expectedValue.Type(),
 			ActualValue:  actual,
-		}
+		})
+	}
+
+	if c.compareNumbers() && isNumber(expected) && isNumber(actual) {
+		return c.number(expected, actual)
+	}
+
+	if c.options.LooseScalars && isScalar(expected) && isScalar(actual) && scalarKind(expected) != scalarKind(actual) {
+		return c.loose(expected, actual, path)
 	}
 
 	if expectedValue.Type() != actualValue.Type() {
-		return false, diff.PrimitiveTypeMismatch{
+		return c.difference(diff.PrimitiveTypeMismatch{
 			ExpectedType: expectedValue.Type(),
 			ActualValue:  actualValue.Interface(),
+		})
+	}
+
+	if c.tooDeep(actualValue, path) {
+		if reflect.DeepEqual(expected, actual) {
+			return nil
 		}
+
+		return c.difference(diff.DepthExceeded{
+			MaxDepth:      c.options.MaxDepth,
+			ExpectedValue: expected,
+			ActualValue:   actual,
+		})
+	}
+
+	leave, differences, ok := c.enter(expectedValue, actualValue, path)
+	if !ok {
+		return differences
 	}
+	defer leave()
 
 	switch actualValue.Kind() {
-	case reflect.Slice:
-		return Slice(expectedValue, actualValue)
+	case reflect.Slice, reflect.Array:
+		return c.slice(expectedValue, actualValue, path)
 
 	case reflect.Map:
-		return Map(expectedValue, actualValue)
+		return c.mapping(expectedValue, actualValue, path)
+
+	case reflect.Struct:
+		return c.structure(expectedValue, actualValue, path)
+
+	case reflect.Ptr:
+		return c.pointer(expectedValue, actualValue, path)
 
 	default:
-		return Primitive(expected, actual)
+		return c.primitive(expected, actual)
+	}
+}
+
+// matcher delegates the comparison to a gomega matcher that appears in the
+// expected value.
+func (c *comparison) matcher(matcher types.GomegaMatcher, actual interface{}) []diff.Difference {
+	success, err := matcher.Match(actual)
+	if err != nil {
+		return c.difference(diff.MatcherFailure{Error: err})
+	}
+
+	if !success {
+		return c.difference(diff.MatcherFailure{FailureMessage: matcher.FailureMessage(actual)})
 	}
+
+	return nil
+}
+
+func (c *comparison) ignored(path Path) bool {
+	return anyMatches(c.options.IgnorePaths, path)
+}
+
+func first(differences []diff.Difference) (bool, diff.Difference) {
+	if len(differences) == 0 {
+		return true, diff.NoDifference{}
+	}
+
+	return false, differences[0]
 }
@@ -130,7 +130,75 @@ var _ = Describe("Compare", func() {
 			equal, difference := deepequal.Compare(expected, actual)
 
 			Expect(equal).To(BeFalse())
-			Expect(difference).To(BeNil())
+			Expect(difference).To(Equal(diff.PrimitiveValueMismatch{
+				ExpectedValue: nil,
+				ActualValue:   actual,
+			}))
+		})
+	})
+
+	Describe("CompareAll", func() {
+		var expected, actual map[string]interface{}
+
+		BeforeEach(func() {
+			expected = map[string]interface{}{
+				"a": 1,
+				"b": []int{1, 2, 3, 4},
+				"c": 3,
+			}
+			actual = map[string]interface{}{
+				"a": 0,
+				"b": []int{1, 0, 3},
+				"d": 3,
+			}
+		})
+
+		It("returns no differences when the objects match", func() {
+			differences := deepequal.CompareAll(expected, expected, deepequal.Options{})
+			Expect(differences).To(BeEmpty())
+		})
+
+		It("returns every difference in a stable order", func() {
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{})
+
+			Expect(differences).To(HaveLen(5))
+			Expect(differences[0]).To(Equal(diff.MapNested{
+				Key: "a",
+				NestedDifference: diff.PrimitiveValueMismatch{
+					ExpectedValue: 1,
+					ActualValue:   0,
+				},
+			}))
+			Expect(differences[1]).To(Equal(diff.MapNested{
+				Key: "b",
+				NestedDifference: diff.SliceNested{
+					Index: 1,
+					NestedDifference: diff.PrimitiveValueMismatch{
+						ExpectedValue: 2,
+						ActualValue:   0,
+					},
+				},
+			}))
+
+			missingElements, isSliceMissingElements := differences[2].(diff.MapNested).NestedDifference.(diff.SliceMissingElements)
+			Expect(isSliceMissingElements).To(BeTrue())
+			Expect(missingElements.MissingElements.Len()).To(Equal(1))
+
+			extraKey, isMapExtraKey := differences[3].(diff.MapExtraKey)
+			Expect(isMapExtraKey).To(BeTrue())
+			Expect(extraKey.ExtraKey).To(Equal("d"))
+
+			missingKey, isMapMissingKey := differences[4].(diff.MapMissingKey)
+			Expect(isMapMissingKey).To(BeTrue())
+			Expect(missingKey.MissingKey).To(Equal("c"))
+		})
+
+		It("stops after MaxDifferences differences", func() {
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{MaxDifferences: 2})
+
+			Expect(differences).To(HaveLen(2))
+			Expect(differences[0].(diff.MapNested).Key).To(Equal("a"))
+			Expect(differences[1].(diff.MapNested).Key).To(Equal("b"))
 		})
 	})
 })
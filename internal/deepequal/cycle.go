@@ -0,0 +1,119 @@
+package deepequal
+
+import (
+	"reflect"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// reference identifies the memory behind a pointer, map or slice, the way
+// reflect.DeepEqual tracks the values it has visited.
+type reference struct {
+	pointer uintptr
+	length  int
+	typ     reflect.Type
+}
+
+func referenceTo(value reflect.Value) (reference, bool) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if value.IsNil() {
+			return reference{}, false
+		}
+
+		return reference{pointer: value.Pointer(), typ: value.Type()}, true
+
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return reference{}, false
+		}
+
+		return reference{pointer: value.Pointer(), length: value.Len(), typ: value.Type()}, true
+
+	default:
+		return reference{}, false
+	}
+}
+
+// ancestors maps the references being walked to the paths they were
+// reached at.
+type ancestors map[reference]Path
+
+// visit is a pair of expected and actual references being compared.
+type visit struct {
+	expected reference
+	actual   reference
+}
+
+// enter records that expected and actual are being walked at path, and
+// returns a function that forgets them once they have been compared. When
+// the same pair of references is already being compared further up, enter
+// returns ok false and no differences: as for reflect.DeepEqual, the pair
+// is equal unless the comparison already under way finds otherwise. When
+// one side refers back to a value that encloses it while the other side is
+// nil or empty, so that the pair cannot settle it, the cycle is reported.
+func (c *comparison) enter(expected reflect.Value, actual reflect.Value, path Path) (leave func(), differences []diff.Difference, ok bool) {
+	expectedReference, expectedTracked := referenceTo(expected)
+	actualReference, actualTracked := referenceTo(actual)
+
+	pair := visit{expected: expectedReference, actual: actualReference}
+	if expectedTracked && actualTracked && c.visiting[pair] {
+		return nil, nil, false
+	}
+
+	expectedTarget, expectedCycles := c.expectedAncestors[expectedReference]
+	expectedCycles = expectedCycles && expectedTracked
+	actualTarget, actualCycles := c.actualAncestors[actualReference]
+	actualCycles = actualCycles && actualTracked
+
+	if (expectedCycles || actualCycles) && !(expectedTracked && actualTracked) {
+		return nil, c.difference(diff.CycleDetected{
+			ExpectedCycles: expectedCycles,
+			ExpectedTarget: expectedTarget,
+			ActualCycles:   actualCycles,
+			ActualTarget:   actualTarget,
+			ExpectedValue:  expected.Interface(),
+			ActualValue:    actual.Interface(),
+		}), false
+	}
+
+	addExpected := expectedTracked && !expectedCycles
+	if addExpected {
+		c.expectedAncestors[expectedReference] = path
+	}
+	addActual := actualTracked && !actualCycles
+	if addActual {
+		c.actualAncestors[actualReference] = path
+	}
+	addPair := expectedTracked && actualTracked
+	if addPair {
+		c.visiting[pair] = true
+	}
+
+	return func() {
+		if addExpected {
+			delete(c.expectedAncestors, expectedReference)
+		}
+		if addActual {
+			delete(c.actualAncestors, actualReference)
+		}
+		if addPair {
+			delete(c.visiting, pair)
+		}
+	}, nil, true
+}
+
+// tooDeep reports whether the values at path are nested deeper than
+// MaxDepth allows walking.
+func (c *comparison) tooDeep(value reflect.Value, path Path) bool {
+	if c.options.MaxDepth <= 0 || len(path) < c.options.MaxDepth {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Ptr:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,129 @@
+package deepequal_test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+type node struct {
+	Name string
+	Next *node
+}
+
+var _ = Describe("Cycles", func() {
+	It("treats values that refer back to the same enclosing values as equal", func() {
+		expected := map[string]interface{}{"name": "a"}
+		expected["self"] = expected
+		actual := map[string]interface{}{"name": "a"}
+		actual["self"] = actual
+
+		Expect(deepequal.CompareAll(expected, actual, deepequal.Options{})).To(BeEmpty())
+	})
+
+	It("still reports differences inside cyclic values", func() {
+		expected := &node{Name: "a"}
+		expected.Next = &node{Name: "b", Next: expected}
+		actual := &node{Name: "a"}
+		actual.Next = &node{Name: "c", Next: actual}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{})
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Path()).To(Equal([]interface{}{diff.FieldName("Next"), diff.FieldName("Name")}))
+	})
+
+	It("treats cycles of different shapes as equal when they unroll to the same values", func() {
+		expected := &node{Name: "a"}
+		expected.Next = expected
+		actual := &node{Name: "a"}
+		actual.Next = &node{Name: "a"}
+		actual.Next.Next = actual.Next
+
+		Expect(reflect.DeepEqual(expected, actual)).To(BeTrue())
+		Expect(deepequal.CompareAll(expected, actual, deepequal.Options{})).To(BeEmpty())
+	})
+
+	It("treats a value that refers to itself as equal to a mutual cycle, as reflect.DeepEqual does", func() {
+		a := &node{Name: "a"}
+		a.Next = a
+		b := &node{Name: "a"}
+		c := &node{Name: "a", Next: b}
+		b.Next = c
+
+		Expect(reflect.DeepEqual(a, b)).To(BeTrue())
+		Expect(deepequal.CompareAll(a, b, deepequal.Options{})).To(BeEmpty())
+	})
+
+	It("reports a cycle on one side only at the path where it happened", func() {
+		expected := &node{Name: "a"}
+		expected.Next = expected
+		actual := &node{Name: "a", Next: &node{Name: "a"}}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{})
+		Expect(differences).To(HaveLen(1))
+
+		path, leaf := diff.Unwrap(differences[0])
+		Expect(path).To(Equal([]interface{}{diff.FieldName("Next"), diff.FieldName("Next")}))
+		Expect(leaf).To(BeAssignableToTypeOf(diff.CycleDetected{}))
+
+		cycle := leaf.(diff.CycleDetected)
+		Expect(cycle.ExpectedCycles).To(BeTrue())
+		Expect(cycle.ExpectedTarget).To(BeEmpty())
+		Expect(cycle.ActualCycles).To(BeFalse())
+	})
+
+	It("reports cycles in slices", func() {
+		expected := []interface{}{"a", nil}
+		expected[1] = expected
+		actual := []interface{}{"a", []interface{}{"a", []interface{}{}}}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{})
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Kind()).To(Equal("cycle detected"))
+		Expect(differences[0].Path()).To(Equal([]interface{}{1, 1}))
+	})
+})
+
+var _ = Describe("MaxDepth", func() {
+	expected := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": 1},
+			"d": 2,
+		},
+	}
+
+	It("reports differing values below the limit as a whole", func() {
+		actual := map[string]interface{}{
+			"a": map[string]interface{}{
+				"b": map[string]interface{}{"c": 3},
+				"d": 2,
+			},
+		}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{MaxDepth: 2})
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Path()).To(Equal([]interface{}{"a", "b"}))
+		Expect(differences[0].Kind()).To(Equal("depth exceeded"))
+		Expect(differences[0].Actual()).To(Equal(map[string]interface{}{"c": 3}))
+	})
+
+	It("compares primitives at the limit", func() {
+		actual := map[string]interface{}{
+			"a": map[string]interface{}{
+				"b": map[string]interface{}{"c": 1},
+				"d": 4,
+			},
+		}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{MaxDepth: 2})
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Kind()).To(Equal("value mismatch"))
+	})
+
+	It("does not report equal values below the limit", func() {
+		Expect(deepequal.CompareAll(expected, expected, deepequal.Options{MaxDepth: 1})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,83 @@
+package deepequal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("IgnorePaths and AnyValuePaths", func() {
+	patterns := func(patterns ...string) []deepequal.Pattern {
+		parsed, err := deepequal.ParsePatterns(patterns)
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	var expected map[string]interface{}
+
+	BeforeEach(func() {
+		expected = map[string]interface{}{
+			"name": "cf",
+			"jobs": []interface{}{
+				map[string]interface{}{"name": "router", "uuid": "1111"},
+				map[string]interface{}{"name": "api", "uuid": "2222"},
+			},
+		}
+	})
+
+	It("skips values selected by IgnorePaths, even when missing or extra", func() {
+		actual := map[string]interface{}{
+			"name": "cf",
+			"jobs": []interface{}{
+				map[string]interface{}{"name": "router", "uuid": "3333"},
+				map[string]interface{}{"name": "api", "password": "secret"},
+			},
+		}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{
+			IgnorePaths: patterns("[jobs][*][uuid]", "[jobs][*][password]"),
+		})
+		Expect(differences).To(BeEmpty())
+	})
+
+	It("only requires keys selected by AnyValuePaths to be present", func() {
+		actual := map[string]interface{}{
+			"name": "cf",
+			"jobs": []interface{}{
+				map[string]interface{}{"name": "router", "uuid": "3333"},
+				map[string]interface{}{"name": "api"},
+			},
+		}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{
+			AnyValuePaths: patterns("[jobs][*][uuid]"),
+		})
+		Expect(differences).To(HaveLen(1))
+
+		sliceNested := differences[0].(diff.MapNested).NestedDifference.(diff.SliceNested)
+		Expect(sliceNested.Index).To(Equal(1))
+
+		missingKeyDifference, isMapMissingKey := sliceNested.NestedDifference.(diff.MapMissingKey)
+		Expect(isMapMissingKey).To(BeTrue())
+		Expect(missingKeyDifference.MissingKey).To(Equal("uuid"))
+	})
+
+	It("still compares values outside the selected paths", func() {
+		actual := map[string]interface{}{
+			"name": "diego",
+			"jobs": expected["jobs"],
+		}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{
+			IgnorePaths: patterns("[jobs]"),
+		})
+		Expect(differences).To(Equal([]diff.Difference{diff.MapNested{
+			Key: "name",
+			NestedDifference: diff.PrimitiveValueMismatch{
+				ExpectedValue: "cf",
+				ActualValue:   "diego",
+			},
+		}}))
+	})
+})
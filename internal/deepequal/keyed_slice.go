@@ -0,0 +1,134 @@
+package deepequal
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// SliceKey aligns the elements of the slices selected by Pattern by the
+// value of their Field instead of by their position.
+type SliceKey struct {
+	Pattern Pattern
+	Field   interface{}
+}
+
+// ParseSliceKeys parses a map from patterns to the field that identifies
+// the elements of the selected slices. Patterns are sorted so that the same
+// one wins every time several select the same slice.
+func ParseSliceKeys(sliceKeys map[string]string) ([]SliceKey, error) {
+	var patterns []string
+	for pattern := range sliceKeys {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var parsed []SliceKey
+	for _, pattern := range patterns {
+		segments, err := ParsePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, SliceKey{Pattern: segments, Field: sliceKeys[pattern]})
+	}
+
+	return parsed, nil
+}
+
+func (c *comparison) sliceKey(path Path) (interface{}, bool) {
+	for _, sliceKey := range c.options.SliceKeys {
+		if sliceKey.Pattern.matchesSlice(path) {
+			return sliceKey.Field, true
+		}
+	}
+
+	return nil, false
+}
+
+// keyedSlice pairs up elements with the same key, regardless of their
+// position. It reports false when the elements cannot be keyed: when one is
+// not a map, lacks the field, or shares its key with another element.
+func (c *comparison) keyedSlice(expectedSlice reflect.Value, actualSlice reflect.Value, field interface{}, path Path) ([]diff.Difference, bool) {
+	expectedKeys, expectedElements, ok := keyElements(expectedSlice, field)
+	if !ok {
+		return nil, false
+	}
+
+	actualKeys, actualElements, ok := keyElements(actualSlice, field)
+	if !ok {
+		return nil, false
+	}
+
+	var differences []diff.Difference
+
+	for _, key := range actualKeys {
+		if c.done() {
+			return differences, true
+		}
+
+		expectedElement, found := expectedElements[key.Value]
+		if !found {
+			differences = append(differences, c.difference(diff.SliceExtraKeyedElement{
+				ExtraKey: key,
+				AllKeys:  actualKeys,
+			})...)
+			continue
+		}
+
+		nested := c.compare(expectedElement, actualElements[key.Value], path.child(key))
+		for _, difference := range nested {
+			differences = append(differences, diff.SliceKeyedNested{
+				Key:              key,
+				NestedDifference: difference,
+			})
+		}
+	}
+
+	for _, key := range expectedKeys {
+		if c.done() {
+			return differences, true
+		}
+
+		if _, found := actualElements[key.Value]; !found {
+			differences = append(differences, c.difference(diff.SliceMissingKeyedElement{
+				MissingKey: key,
+				AllKeys:    actualKeys,
+			})...)
+		}
+	}
+
+	return differences, true
+}
+
+func keyElements(slice reflect.Value, field interface{}) ([]diff.ElementKey, map[interface{}]interface{}, bool) {
+	var keys []diff.ElementKey
+	elements := map[interface{}]interface{}{}
+
+	for i := 0; i < slice.Len(); i++ {
+		element := reflect.ValueOf(slice.Index(i).Interface())
+		if element.Kind() != reflect.Map || !reflect.TypeOf(field).AssignableTo(element.Type().Key()) {
+			return nil, nil, false
+		}
+
+		value := element.MapIndex(reflect.ValueOf(field))
+		if !value.IsValid() {
+			return nil, nil, false
+		}
+
+		keyValue := value.Interface()
+		if keyValue == nil || !reflect.TypeOf(keyValue).Comparable() {
+			return nil, nil, false
+		}
+
+		if _, duplicate := elements[keyValue]; duplicate {
+			return nil, nil, false
+		}
+
+		keys = append(keys, diff.ElementKey{Field: field, Value: keyValue})
+		elements[keyValue] = element.Interface()
+	}
+
+	return keys, elements, true
+}
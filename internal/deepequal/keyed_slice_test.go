@@ -0,0 +1,115 @@
+package deepequal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("SliceKeys", func() {
+	var options deepequal.Options
+
+	BeforeEach(func() {
+		pattern, err := deepequal.ParsePattern("instance_groups[*]")
+		Expect(err).NotTo(HaveOccurred())
+
+		options = deepequal.Options{
+			SliceKeys: []deepequal.SliceKey{{Pattern: pattern, Field: "name"}},
+		}
+	})
+
+	group := func(name string, instances int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "instances": instances}
+	}
+
+	It("pairs elements by key regardless of their position", func() {
+		expected := map[string]interface{}{"instance_groups": []interface{}{group("router", 1), group("api", 2)}}
+		actual := map[string]interface{}{"instance_groups": []interface{}{group("api", 2), group("router", 1)}}
+
+		Expect(deepequal.CompareAll(expected, actual, options)).To(BeEmpty())
+	})
+
+	It("reports nested differences under the element key", func() {
+		expected := map[string]interface{}{"instance_groups": []interface{}{group("router", 1), group("api", 2)}}
+		actual := map[string]interface{}{"instance_groups": []interface{}{group("uaa", 1), group("router", 1), group("api", 3)}}
+
+		differences := deepequal.CompareAll(expected, actual, options)
+		Expect(differences).To(HaveLen(2))
+
+		extraElement := differences[0].(diff.MapNested).NestedDifference
+		Expect(extraElement).To(Equal(diff.SliceExtraKeyedElement{
+			ExtraKey: diff.ElementKey{Field: "name", Value: "uaa"},
+			AllKeys: []diff.ElementKey{
+				{Field: "name", Value: "uaa"},
+				{Field: "name", Value: "router"},
+				{Field: "name", Value: "api"},
+			},
+		}))
+
+		Expect(differences[1]).To(Equal(diff.MapNested{
+			Key: "instance_groups",
+			NestedDifference: diff.SliceKeyedNested{
+				Key: diff.ElementKey{Field: "name", Value: "api"},
+				NestedDifference: diff.MapNested{
+					Key: "instances",
+					NestedDifference: diff.PrimitiveValueMismatch{
+						ExpectedValue: 2,
+						ActualValue:   3,
+					},
+				},
+			},
+		}))
+	})
+
+	It("reports expected elements whose key is not in actual", func() {
+		expected := map[string]interface{}{"instance_groups": []interface{}{group("router", 1), group("api", 2)}}
+		actual := map[string]interface{}{"instance_groups": []interface{}{group("router", 1)}}
+
+		differences := deepequal.CompareAll(expected, actual, options)
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].(diff.MapNested).NestedDifference).To(Equal(diff.SliceMissingKeyedElement{
+			MissingKey: diff.ElementKey{Field: "name", Value: "api"},
+			AllKeys:    []diff.ElementKey{{Field: "name", Value: "router"}},
+		}))
+	})
+
+	It("matches nested patterns through keyed elements", func() {
+		jobsPattern, err := deepequal.ParsePattern("instance_groups[*][jobs][*]")
+		Expect(err).NotTo(HaveOccurred())
+		options.SliceKeys = append(options.SliceKeys, deepequal.SliceKey{Pattern: jobsPattern, Field: "name"})
+
+		expected := map[string]interface{}{"instance_groups": []interface{}{
+			map[string]interface{}{"name": "router", "jobs": []interface{}{
+				map[string]interface{}{"name": "gorouter"},
+				map[string]interface{}{"name": "metron"},
+			}},
+		}}
+		actual := map[string]interface{}{"instance_groups": []interface{}{
+			map[string]interface{}{"name": "router", "jobs": []interface{}{
+				map[string]interface{}{"name": "metron"},
+				map[string]interface{}{"name": "gorouter"},
+			}},
+		}}
+
+		Expect(deepequal.CompareAll(expected, actual, options)).To(BeEmpty())
+	})
+
+	It("falls back to comparing by position when elements cannot be keyed", func() {
+		expected := map[string]interface{}{"instance_groups": []interface{}{group("router", 1), "api"}}
+		actual := map[string]interface{}{"instance_groups": []interface{}{"api", group("router", 1)}}
+
+		differences := deepequal.CompareAll(expected, actual, options)
+		Expect(differences).To(HaveLen(2))
+		Expect(differences[0].(diff.MapNested).NestedDifference.(diff.SliceNested).Index).To(Equal(0))
+	})
+
+	It("falls back to comparing by position when keys are duplicated", func() {
+		expected := map[string]interface{}{"instance_groups": []interface{}{group("router", 1), group("router", 2)}}
+		actual := map[string]interface{}{"instance_groups": []interface{}{group("router", 2), group("router", 1)}}
+
+		differences := deepequal.CompareAll(expected, actual, options)
+		Expect(differences).To(HaveLen(2))
+		Expect(differences[0].(diff.MapNested).NestedDifference.(diff.SliceNested).Index).To(Equal(0))
+	})
+})
@@ -0,0 +1,92 @@
+package deepequal
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// loose compares two scalars of different kinds by their canonical string
+// form. Scalars that are only equal that way are passed to OnLooseMatch.
+func (c *comparison) loose(expected interface{}, actual interface{}, path Path) []diff.Difference {
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	form := canonical(expected)
+	if form != canonical(actual) {
+		return c.difference(diff.PrimitiveValueMismatch{
+			ExpectedValue: expected,
+			ActualValue:   actual,
+		})
+	}
+
+	if c.options.OnLooseMatch != nil {
+		c.options.OnLooseMatch(diff.LooseMatch{
+			Location:      path,
+			ExpectedValue: expected,
+			ActualValue:   actual,
+			Form:          form,
+		})
+	}
+
+	return nil
+}
+
+// canonical renders a scalar so that strings holding a number or a boolean
+// render like that number or boolean: "1.0", 1.0 and 1 all render as "1".
+func canonical(scalar interface{}) string {
+	if text, ok := scalar.(string); ok {
+		if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return strconv.FormatInt(value, 10)
+		}
+		if value, err := strconv.ParseFloat(text, 64); err == nil {
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		if lower := strings.ToLower(text); lower == "true" || lower == "false" {
+			return lower
+		}
+
+		return text
+	}
+
+	if value, ok := scalar.(bool); ok {
+		return strconv.FormatBool(value)
+	}
+
+	number, _ := numberValue(scalar)
+	switch number := number.(type) {
+	case int64:
+		return strconv.FormatInt(number, 10)
+	case uint64:
+		return strconv.FormatUint(number, 10)
+	case *big.Int:
+		return number.String()
+	default:
+		return strconv.FormatFloat(number.(float64), 'f', -1, 64)
+	}
+}
+
+// scalarKind tells strings, booleans and numbers apart.
+func scalarKind(scalar interface{}) string {
+	switch scalar.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	default:
+		return "number"
+	}
+}
+
+func isScalar(input interface{}) bool {
+	switch input.(type) {
+	case string, bool:
+		return true
+	default:
+		return isNumber(input)
+	}
+}
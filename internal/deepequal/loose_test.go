@@ -0,0 +1,117 @@
+package deepequal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("LooseScalars", func() {
+	var (
+		looseMatches []diff.Difference
+		options      deepequal.Options
+	)
+
+	BeforeEach(func() {
+		looseMatches = nil
+		options = deepequal.Options{
+			LooseScalars: true,
+			OnLooseMatch: func(looseMatch diff.Difference) {
+				looseMatches = append(looseMatches, looseMatch)
+			},
+		}
+	})
+
+	It("treats scalars with the same string form as equal and reports them", func() {
+		differences := deepequal.CompareAll(
+			map[interface{}]interface{}{"version": 1.0, "enabled": "true", "name": "web"},
+			map[interface{}]interface{}{"version": "1.0", "enabled": true, "name": "web"},
+			options,
+		)
+		Expect(differences).To(BeEmpty())
+		Expect(looseMatches).To(Equal([]diff.Difference{
+			diff.LooseMatch{
+				Location:      []interface{}{"enabled"},
+				ExpectedValue: "true",
+				ActualValue:   true,
+				Form:          "true",
+			},
+			diff.LooseMatch{
+				Location:      []interface{}{"version"},
+				ExpectedValue: 1.0,
+				ActualValue:   "1.0",
+				Form:          "1",
+			},
+		}))
+	})
+
+	It("reports scalars with different string forms as value mismatches", func() {
+		differences := deepequal.CompareAll(
+			map[interface{}]interface{}{"version": 1.0},
+			map[interface{}]interface{}{"version": "1.1"},
+			options,
+		)
+		Expect(differences).To(Equal([]diff.Difference{
+			diff.MapNested{
+				Key: "version",
+				NestedDifference: diff.PrimitiveValueMismatch{
+					ExpectedValue: 1.0,
+					ActualValue:   "1.1",
+				},
+			},
+		}))
+		Expect(looseMatches).To(BeEmpty())
+	})
+
+	It("compares scalars of the same kind as usual", func() {
+		differences := deepequal.CompareAll(
+			map[interface{}]interface{}{"version": "1.10", "size": "1e3", "enabled": "TRUE"},
+			map[interface{}]interface{}{"version": "1.1", "size": "1000", "enabled": "true"},
+			options,
+		)
+		Expect(differences).To(ConsistOf(
+			diff.MapNested{Key: "enabled", NestedDifference: diff.PrimitiveValueMismatch{ExpectedValue: "TRUE", ActualValue: "true"}},
+			diff.MapNested{Key: "size", NestedDifference: diff.PrimitiveValueMismatch{ExpectedValue: "1e3", ActualValue: "1000"}},
+			diff.MapNested{Key: "version", NestedDifference: diff.PrimitiveValueMismatch{ExpectedValue: "1.10", ActualValue: "1.1"}},
+		))
+		Expect(looseMatches).To(BeEmpty())
+	})
+
+	It("does not report loose matches found while pairing unordered elements", func() {
+		options.IgnoreOrder = true
+		differences := deepequal.CompareAll([]interface{}{"1", 1}, []interface{}{1, "1"}, options)
+		Expect(differences).To(BeEmpty())
+		Expect(looseMatches).To(BeEmpty())
+	})
+
+	It("reports the loose matches of the unordered elements it pairs", func() {
+		options.IgnoreOrderPaths = []deepequal.Pattern{{"ports"}}
+		differences := deepequal.CompareAll(
+			map[interface{}]interface{}{"ports": []interface{}{"8080", 443}},
+			map[interface{}]interface{}{"ports": []interface{}{"443", 8080}},
+			options,
+		)
+		Expect(differences).To(BeEmpty())
+		Expect(looseMatches).To(Equal([]diff.Difference{
+			diff.LooseMatch{
+				Location:      []interface{}{"ports", 1},
+				ExpectedValue: "8080",
+				ActualValue:   8080,
+				Form:          "8080",
+			},
+			diff.LooseMatch{
+				Location:      []interface{}{"ports", 0},
+				ExpectedValue: 443,
+				ActualValue:   "443",
+				Form:          "443",
+			},
+		}))
+	})
+
+	It("still reports type mismatches between scalars and collections", func() {
+		differences := deepequal.CompareAll("1", []interface{}{1}, options)
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Kind()).To(Equal("type mismatch"))
+	})
+})
@@ -1,37 +1,69 @@
 package deepequal
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
 )
 
 func Map(expectedMap reflect.Value, actualMap reflect.Value) (bool, diff.Difference) {
-	for _, key := range actualMap.MapKeys() {
+	return first(newComparison(Options{MaxDifferences: 1}).mapping(expectedMap, actualMap, nil))
+}
+
+func (c *comparison) mapping(expectedMap reflect.Value, actualMap reflect.Value, path Path) []diff.Difference {
+	var differences []diff.Difference
+
+	for _, key := range sortedKeys(actualMap) {
+		if c.done() {
+			return differences
+		}
+
 		if expectedMap.MapIndex(key).Kind() == reflect.Invalid {
-			return false, diff.MapExtraKey{
+			if c.options.IgnoreExtraKeys || c.ignored(path.child(key.Interface())) {
+				continue
+			}
+
+			differences = append(differences, c.difference(diff.MapExtraKey{
 				ExtraKey: key.Interface(),
 				AllKeys:  actualMap.MapKeys(),
-			}
+			})...)
+			continue
 		}
 
-		equal, difference := Compare(expectedMap.MapIndex(key).Interface(), actualMap.MapIndex(key).Interface())
-		if !equal {
-			return false, diff.MapNested{
+		nested := c.compare(expectedMap.MapIndex(key).Interface(), actualMap.MapIndex(key).Interface(), path.child(key.Interface()))
+		for _, difference := range nested {
+			differences = append(differences, diff.MapNested{
 				Key:              key.Interface(),
 				NestedDifference: difference,
-			}
+			})
 		}
 	}
 
-	for _, key := range expectedMap.MapKeys() {
-		if actualMap.MapIndex(key).Kind() == reflect.Invalid {
-			return false, diff.MapMissingKey{
+	for _, key := range sortedKeys(expectedMap) {
+		if c.done() {
+			return differences
+		}
+
+		if actualMap.MapIndex(key).Kind() == reflect.Invalid && !c.ignored(path.child(key.Interface())) {
+			differences = append(differences, c.difference(diff.MapMissingKey{
 				MissingKey: key.Interface(),
 				AllKeys:    actualMap.MapKeys(),
-			}
+			})...)
 		}
 	}
 
-	return true, diff.NoDifference{}
+	return differences
+}
+
+// sortedKeys orders map keys by their printed form so that differences are
+// reported in the same order on every run.
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	return keys
 }
@@ -65,4 +65,34 @@ var _ = Describe("Map", func() {
 		Expect(missingKeyDifference.AllKeys).To(HaveLen(1))
 		Expect(missingKeyDifference.AllKeys[0].String()).To(Equal("a"))
 	})
+
+	Context("when IgnoreExtraKeys is set", func() {
+		options := deepequal.Options{IgnoreExtraKeys: true}
+
+		It("ignores keys that are only in the actual map, at any depth", func() {
+			expected := map[string]interface{}{"a": map[string]interface{}{"b": 1}}
+			actual := map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}, "d": 3}
+
+			Expect(deepequal.CompareAll(expected, actual, options)).To(BeEmpty())
+		})
+
+		It("still reports missing keys and mismatched values", func() {
+			expected := map[string]interface{}{"a": 1, "b": 2}
+			actual := map[string]interface{}{"a": 0, "c": 3}
+
+			differences := deepequal.CompareAll(expected, actual, options)
+			Expect(differences).To(HaveLen(2))
+			Expect(differences[0]).To(Equal(diff.MapNested{
+				Key: "a",
+				NestedDifference: diff.PrimitiveValueMismatch{
+					ExpectedValue: 1,
+					ActualValue:   0,
+				},
+			}))
+
+			missingKeyDifference, isMapMissingKey := differences[1].(diff.MapMissingKey)
+			Expect(isMapMissingKey).To(BeTrue())
+			Expect(missingKeyDifference.MissingKey).To(Equal("b"))
+		})
+	})
 })
@@ -0,0 +1,54 @@
+package deepequal_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+type erroringMatcher struct{}
+
+func (erroringMatcher) Match(actual interface{}) (bool, error) {
+	return false, errors.New("cannot match")
+}
+
+func (erroringMatcher) FailureMessage(actual interface{}) string {
+	return ""
+}
+
+func (erroringMatcher) NegatedFailureMessage(actual interface{}) string {
+	return ""
+}
+
+var _ = Describe("embedded matchers", func() {
+	It("returns no difference when the matcher succeeds", func() {
+		expected := map[string]interface{}{"password": HaveLen(6)}
+		actual := map[string]interface{}{"password": "secret"}
+
+		Expect(deepequal.CompareAll(expected, actual, deepequal.Options{})).To(BeEmpty())
+	})
+
+	It("returns the failure message of a failing matcher at its path", func() {
+		expected := map[string]interface{}{"ports": []interface{}{BeNumerically(">", 1024)}}
+		actual := map[string]interface{}{"ports": []interface{}{80}}
+
+		differences := deepequal.CompareAll(expected, actual, deepequal.Options{})
+		Expect(differences).To(HaveLen(1))
+
+		sliceNested := differences[0].(diff.MapNested).NestedDifference.(diff.SliceNested)
+		Expect(sliceNested.Index).To(Equal(0))
+		Expect(sliceNested.NestedDifference).To(Equal(diff.MatcherFailure{
+			FailureMessage: BeNumerically(">", 1024).FailureMessage(80),
+		}))
+	})
+
+	It("returns the error of a matcher that errors", func() {
+		differences := deepequal.CompareAll(erroringMatcher{}, "anything", deepequal.Options{})
+		Expect(differences).To(Equal([]diff.Difference{diff.MatcherFailure{
+			Error: errors.New("cannot match"),
+		}}))
+	})
+})
@@ -0,0 +1,141 @@
+package deepequal
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// compareNumbers reports whether numbers are compared by value, whatever
+// their kinds.
+func (c *comparison) compareNumbers() bool {
+	return c.options.NumericValues || c.options.AbsoluteTolerance > 0 || c.options.RelativeTolerance > 0
+}
+
+// number compares two numbers by value. Integers are compared exactly, and
+// as soon as either side is a float both are compared as float64 within the
+// configured tolerances.
+func (c *comparison) number(expected interface{}, actual interface{}) []diff.Difference {
+	expectedNumber, _ := numberValue(expected)
+	actualNumber, _ := numberValue(actual)
+
+	if c.numbersEqual(expectedNumber, actualNumber) {
+		return nil
+	}
+
+	return c.difference(diff.PrimitiveValueMismatch{
+		ExpectedValue: expected,
+		ActualValue:   actual,
+	})
+}
+
+func (c *comparison) numbersEqual(expected interface{}, actual interface{}) bool {
+	switch expected := expected.(type) {
+	case int64:
+		switch actual := actual.(type) {
+		case int64:
+			return expected == actual
+		case uint64:
+			return expected >= 0 && uint64(expected) == actual
+		}
+
+	case uint64:
+		switch actual := actual.(type) {
+		case int64:
+			return actual >= 0 && uint64(actual) == expected
+		case uint64:
+			return expected == actual
+		}
+	}
+
+	expectedInteger, expectedIsInteger := toBigInt(expected)
+	actualInteger, actualIsInteger := toBigInt(actual)
+	if expectedIsInteger && actualIsInteger {
+		return expectedInteger.Cmp(actualInteger) == 0
+	}
+
+	return c.floatsEqual(toFloat(expected), toFloat(actual))
+}
+
+func (c *comparison) floatsEqual(expected float64, actual float64) bool {
+	if expected == actual {
+		return true
+	}
+
+	difference := math.Abs(expected - actual)
+	if difference <= c.options.AbsoluteTolerance {
+		return true
+	}
+
+	return difference <= c.options.RelativeTolerance*math.Max(math.Abs(expected), math.Abs(actual))
+}
+
+// numberValue converts any integer to int64 or uint64, and any float to
+// float64. The numbers that the JSON decoder keeps as json.Number are
+// converted the same way, or to a *big.Int when they overflow 64 bits.
+func numberValue(input interface{}) (interface{}, bool) {
+	if number, ok := input.(json.Number); ok {
+		if value, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+			return value, true
+		}
+		if value, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+			return value, true
+		}
+		if value, ok := new(big.Int).SetString(string(number), 10); ok {
+			return value, true
+		}
+		if value, err := number.Float64(); err == nil {
+			return value, true
+		}
+
+		return nil, false
+	}
+
+	value := reflect.ValueOf(input)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return nil, false
+	}
+}
+
+func toBigInt(number interface{}) (*big.Int, bool) {
+	switch number := number.(type) {
+	case int64:
+		return big.NewInt(number), true
+	case uint64:
+		return new(big.Int).SetUint64(number), true
+	case *big.Int:
+		return number, true
+	default:
+		return nil, false
+	}
+}
+
+func toFloat(number interface{}) float64 {
+	switch number := number.(type) {
+	case int64:
+		return float64(number)
+	case uint64:
+		return float64(number)
+	case *big.Int:
+		value, _ := new(big.Float).SetInt(number).Float64()
+		return value
+	default:
+		return number.(float64)
+	}
+}
+
+func isNumber(input interface{}) bool {
+	_, ok := numberValue(input)
+	return ok
+}
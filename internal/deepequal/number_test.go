@@ -0,0 +1,51 @@
+package deepequal_test
+
+import (
+	"encoding/json"
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("NumericValues", func() {
+	numeric := deepequal.Options{NumericValues: true}
+
+	It("compares numbers of different kinds by value", func() {
+		Expect(deepequal.CompareAll(8080, uint64(8080), numeric)).To(BeEmpty())
+		Expect(deepequal.CompareAll(8080, 8080.0, numeric)).To(BeEmpty())
+		Expect(deepequal.CompareAll(int8(-1), float32(-1), numeric)).To(BeEmpty())
+		Expect(deepequal.CompareAll(json.Number("18446744073709551616"), json.Number("18446744073709551616"), numeric)).To(BeEmpty())
+	})
+
+	It("reports numbers that differ as value mismatches", func() {
+		Expect(deepequal.CompareAll(8080, 8081.0, numeric)).To(Equal([]diff.Difference{
+			diff.PrimitiveValueMismatch{ExpectedValue: 8080, ActualValue: 8081.0},
+		}))
+	})
+
+	It("does not confuse negative and large unsigned integers", func() {
+		Expect(deepequal.CompareAll(-1, uint64(math.MaxUint64), numeric)).To(HaveLen(1))
+		Expect(deepequal.CompareAll(uint64(math.MaxUint64), json.Number("18446744073709551616"), numeric)).To(HaveLen(1))
+	})
+
+	It("still reports type mismatches between numbers and other values", func() {
+		differences := deepequal.CompareAll(8080, "8080", numeric)
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Kind()).To(Equal("type mismatch"))
+	})
+
+	It("lets floats differ within an absolute tolerance", func() {
+		options := deepequal.Options{AbsoluteTolerance: 0.5}
+		Expect(deepequal.CompareAll(10, 10.4, options)).To(BeEmpty())
+		Expect(deepequal.CompareAll(10, 10.6, options)).To(HaveLen(1))
+	})
+
+	It("lets floats differ within a relative tolerance", func() {
+		options := deepequal.Options{RelativeTolerance: 0.01}
+		Expect(deepequal.CompareAll(1000.0, 1009.0, options)).To(BeEmpty())
+		Expect(deepequal.CompareAll(1000.0, 1011.0, options)).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,157 @@
+package deepequal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// Path is the list of map keys, slice indexes and struct fields that lead
+// from the root of a document to a value.
+type Path []interface{}
+
+func (path Path) child(segment interface{}) Path {
+	child := make(Path, len(path), len(path)+1)
+	copy(child, path)
+
+	return append(child, segment)
+}
+
+func (path Path) String() string {
+	return diff.FormatPath(path)
+}
+
+// Pattern selects paths. Each segment matches a map key or slice index by
+// its printed form, and "*" matches any single key or index.
+type Pattern []string
+
+// ParsePattern parses patterns written the way failure messages print
+// paths, such as "[instance_groups][*][jobs]". A leading segment may omit
+// its brackets, as in "instance_groups[*][jobs]".
+func ParsePattern(pattern string) (Pattern, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("invalid path pattern %q: no segments", pattern)
+	}
+
+	var segments Pattern
+
+	rest := pattern
+	if !strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "[")
+		if end == -1 {
+			end = len(rest)
+		}
+
+		segments = append(segments, rest[:end])
+		rest = rest[end:]
+	}
+
+	for rest != "" {
+		if !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("invalid path pattern %q: expected '[' at %q", pattern, rest)
+		}
+
+		end := strings.Index(rest, "]")
+		if end == -1 {
+			return nil, fmt.Errorf("invalid path pattern %q: missing ']'", pattern)
+		}
+
+		segments = append(segments, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	return segments, nil
+}
+
+// ParsePatterns parses each of the given patterns with ParsePattern.
+func ParsePatterns(patterns []string) ([]Pattern, error) {
+	var parsed []Pattern
+	for _, pattern := range patterns {
+		segments, err := ParsePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, segments)
+	}
+
+	return parsed, nil
+}
+
+// PathOptions are the Options that select paths, written as text the way
+// the matchers and deepdiff take them.
+type PathOptions struct {
+	IgnoreOrderPaths []string
+	SliceKeys        map[string]string
+	IgnorePaths      []string
+	AnyValuePaths    []string
+}
+
+// WithPaths returns options with the given path options parsed into it.
+func (options Options) WithPaths(paths PathOptions) (Options, error) {
+	var err error
+
+	options.IgnoreOrderPaths, err = ParsePatterns(paths.IgnoreOrderPaths)
+	if err != nil {
+		return Options{}, err
+	}
+
+	options.IgnorePaths, err = ParsePatterns(paths.IgnorePaths)
+	if err != nil {
+		return Options{}, err
+	}
+
+	options.AnyValuePaths, err = ParsePatterns(paths.AnyValuePaths)
+	if err != nil {
+		return Options{}, err
+	}
+
+	options.SliceKeys, err = ParseSliceKeys(paths.SliceKeys)
+	if err != nil {
+		return Options{}, err
+	}
+
+	return options, nil
+}
+
+// Matches reports whether the pattern selects exactly the given path.
+func (pattern Pattern) Matches(path Path) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for i, segment := range pattern {
+		if segment != "*" && segment != fmt.Sprintf("%+v", path[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesSlice reports whether the pattern selects the slice at path,
+// either directly or through its elements, as in "[releases][*]".
+func (pattern Pattern) matchesSlice(path Path) bool {
+	return pattern.Matches(path) || pattern.Matches(path.child("*"))
+}
+
+func anyMatches(patterns []Pattern, path Path) bool {
+	for _, pattern := range patterns {
+		if pattern.Matches(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func anyMatchesSlice(patterns []Pattern, path Path) bool {
+	for _, pattern := range patterns {
+		if pattern.matchesSlice(path) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package deepequal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+)
+
+var _ = Describe("Path", func() {
+	It("prints like the paths in failure messages", func() {
+		Expect(deepequal.Path{"population", 1980, "absolute"}.String()).To(Equal("[population][1980][absolute]"))
+	})
+})
+
+var _ = Describe("ParsePattern", func() {
+	It("parses bracketed segments", func() {
+		pattern, err := deepequal.ParsePattern("[instance_groups][*][jobs]")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pattern).To(Equal(deepequal.Pattern{"instance_groups", "*", "jobs"}))
+	})
+
+	It("allows the first segment to omit its brackets", func() {
+		pattern, err := deepequal.ParsePattern("instance_groups[*]")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pattern).To(Equal(deepequal.Pattern{"instance_groups", "*"}))
+
+		pattern, err = deepequal.ParsePattern("releases")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pattern).To(Equal(deepequal.Pattern{"releases"}))
+	})
+
+	It("returns an error for malformed patterns", func() {
+		_, err := deepequal.ParsePattern("[jobs")
+		Expect(err).To(MatchError(`invalid path pattern "[jobs": missing ']'`))
+
+		_, err = deepequal.ParsePattern("[jobs]name")
+		Expect(err).To(MatchError(`invalid path pattern "[jobs]name": expected '[' at "name"`))
+
+		_, err = deepequal.ParsePattern("")
+		Expect(err).To(MatchError(`invalid path pattern "": no segments`))
+	})
+
+	Describe("Matches", func() {
+		It("matches keys and indexes by their printed form", func() {
+			pattern := deepequal.Pattern{"population", "1980"}
+			Expect(pattern.Matches(deepequal.Path{"population", 1980})).To(BeTrue())
+			Expect(pattern.Matches(deepequal.Path{"population", 1990})).To(BeFalse())
+		})
+
+		It("matches any key or index with a wildcard", func() {
+			pattern := deepequal.Pattern{"jobs", "*", "name"}
+			Expect(pattern.Matches(deepequal.Path{"jobs", 3, "name"})).To(BeTrue())
+			Expect(pattern.Matches(deepequal.Path{"jobs", 3, "release"})).To(BeFalse())
+		})
+
+		It("does not match paths of a different length", func() {
+			pattern := deepequal.Pattern{"jobs", "*"}
+			Expect(pattern.Matches(deepequal.Path{"jobs"})).To(BeFalse())
+			Expect(pattern.Matches(deepequal.Path{"jobs", 0, "name"})).To(BeFalse())
+		})
+	})
+})
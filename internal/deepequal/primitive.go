@@ -7,12 +7,16 @@ import (
 )
 
 func Primitive(expectedPrimitive interface{}, actualPrimitive interface{}) (bool, diff.Difference) {
+	return first(newComparison(Options{MaxDifferences: 1}).primitive(expectedPrimitive, actualPrimitive))
+}
+
+func (c *comparison) primitive(expectedPrimitive interface{}, actualPrimitive interface{}) []diff.Difference {
 	if !reflect.DeepEqual(expectedPrimitive, actualPrimitive) {
-		return false, diff.PrimitiveValueMismatch{
+		return c.difference(diff.PrimitiveValueMismatch{
 			ExpectedValue: expectedPrimitive,
 			ActualValue:   actualPrimitive,
-		}
+		})
 	}
 
-	return true, diff.NoDifference{}
+	return nil
 }
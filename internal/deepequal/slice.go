@@ -7,29 +7,154 @@ import (
 )
 
 func Slice(expectedSlice reflect.Value, actualSlice reflect.Value) (bool, diff.Difference) {
+	return first(newComparison(Options{MaxDifferences: 1}).slice(expectedSlice, actualSlice, nil))
+}
+
+func (c *comparison) slice(expectedSlice reflect.Value, actualSlice reflect.Value, path Path) []diff.Difference {
+	if field, ok := c.sliceKey(path); ok {
+		if differences, ok := c.keyedSlice(expectedSlice, actualSlice, field, path); ok {
+			return differences
+		}
+	}
+
+	if c.options.IgnoreOrder || anyMatchesSlice(c.options.IgnoreOrderPaths, path) {
+		return c.unorderedSlice(expectedSlice, actualSlice, path)
+	}
+
+	var differences []diff.Difference
+
 	for i := 0; i < actualSlice.Len(); i++ {
+		if c.done() {
+			return differences
+		}
+
 		if i >= expectedSlice.Len() {
-			return false, diff.SliceExtraElements{
+			return append(differences, c.difference(diff.SliceExtraElements{
 				ExtraElements: actualSlice.Slice(i, actualSlice.Len()),
 				AllElements:   actualSlice,
-			}
+			})...)
 		}
 
-		equal, difference := Compare(expectedSlice.Index(i).Interface(), actualSlice.Index(i).Interface())
-		if !equal {
-			return false, diff.SliceNested{
+		nested := c.compare(expectedSlice.Index(i).Interface(), actualSlice.Index(i).Interface(), path.child(i))
+		for _, difference := range nested {
+			differences = append(differences, diff.SliceNested{
 				Index:            i,
 				NestedDifference: difference,
-			}
+			})
 		}
 	}
 
-	if expectedSlice.Len() > actualSlice.Len() {
-		return false, diff.SliceMissingElements{
+	if expectedSlice.Len() > actualSlice.Len() && !c.done() {
+		differences = append(differences, c.difference(diff.SliceMissingElements{
 			MissingElements: expectedSlice.Slice(actualSlice.Len(), expectedSlice.Len()),
 			AllElements:     actualSlice,
+		})...)
+	}
+
+	return differences
+}
+
+// unorderedSlice pairs each expected element with an equal actual element,
+// wherever it is, and reports the elements left over on either side. When
+// elements are equal to more than one element on the other side, as they
+// can be with IgnoreExtraKeys, embedded matchers or tolerances, the pairing
+// is a maximum matching, so that no element takes the only partner of
+// another. Only the loose matches of the chosen pairs are passed on to
+// OnLooseMatch.
+func (c *comparison) unorderedSlice(expectedSlice reflect.Value, actualSlice reflect.Value, path Path) []diff.Difference {
+	candidates := make([][]int, expectedSlice.Len())
+	looseMatches := make([]map[int][]diff.Difference, expectedSlice.Len())
+	for i := range candidates {
+		for j := 0; j < actualSlice.Len(); j++ {
+			equal, loose := c.equal(expectedSlice.Index(i).Interface(), actualSlice.Index(j).Interface(), path.child(j))
+			if !equal {
+				continue
+			}
+
+			candidates[i] = append(candidates[i], j)
+			if len(loose) > 0 {
+				if looseMatches[i] == nil {
+					looseMatches[i] = map[int][]diff.Difference{}
+				}
+				looseMatches[i][j] = loose
+			}
+		}
+	}
+
+	pairs := maximumMatching(candidates, actualSlice.Len())
+
+	matched := make([]bool, actualSlice.Len())
+	missing := reflect.MakeSlice(reflect.SliceOf(expectedSlice.Type().Elem()), 0, 0)
+	for i, j := range pairs {
+		if j < 0 {
+			missing = reflect.Append(missing, expectedSlice.Index(i))
+			continue
+		}
+
+		matched[j] = true
+		if c.options.OnLooseMatch != nil {
+			for _, looseMatch := range looseMatches[i][j] {
+				c.options.OnLooseMatch(looseMatch)
+			}
+		}
+	}
+
+	extra := reflect.MakeSlice(reflect.SliceOf(actualSlice.Type().Elem()), 0, 0)
+	for j := 0; j < actualSlice.Len(); j++ {
+		if !matched[j] {
+			extra = reflect.Append(extra, actualSlice.Index(j))
+		}
+	}
+
+	if missing.Len() == 0 && extra.Len() == 0 {
+		return nil
+	}
+
+	return c.difference(diff.SliceUnorderedMismatch{
+		MissingElements: missing,
+		ExtraElements:   extra,
+	})
+}
+
+// maximumMatching pairs each expected element with one of its candidate
+// actual elements, pairing as many as possible by augmenting paths. It
+// returns the actual element of each expected element, or -1.
+func maximumMatching(candidates [][]int, actualLen int) []int {
+	owner := make([]int, actualLen)
+	for j := range owner {
+		owner[j] = -1
+	}
+
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for _, j := range candidates[i] {
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+
+			if owner[j] < 0 || augment(owner[j], visited) {
+				owner[j] = i
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := range candidates {
+		augment(i, make([]bool, actualLen))
+	}
+
+	pairs := make([]int, len(candidates))
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	for j, i := range owner {
+		if i >= 0 {
+			pairs[i] = j
 		}
 	}
 
-	return true, diff.NoDifference{}
+	return pairs
 }
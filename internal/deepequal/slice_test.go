@@ -73,4 +73,83 @@ var _ = Describe("Slice", func() {
 		Expect(int(missingElementsDifference.AllElements.Index(0).Int())).To(Equal(1))
 		Expect(int(missingElementsDifference.AllElements.Index(1).Int())).To(Equal(2))
 	})
+
+	Context("when ignoring order", func() {
+		It("returns no difference when the slices contain the same elements in a different order", func() {
+			expected := []interface{}{"a", "b", "b", map[string]interface{}{"c": 1}}
+			actual := []interface{}{map[string]interface{}{"c": 1}, "b", "a", "b"}
+
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{IgnoreOrder: true})
+			Expect(differences).To(BeEmpty())
+		})
+
+		It("reports the unmatched elements on each side", func() {
+			expected := []interface{}{"a", "b", "b"}
+			actual := []interface{}{"b", "c", "a", "d"}
+
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{IgnoreOrder: true})
+			Expect(differences).To(HaveLen(1))
+
+			mismatch, isSliceUnorderedMismatch := differences[0].(diff.SliceUnorderedMismatch)
+			Expect(isSliceUnorderedMismatch).To(BeTrue())
+			Expect(mismatch.MissingElements.Interface()).To(Equal([]interface{}{"b"}))
+			Expect(mismatch.ExtraElements.Interface()).To(Equal([]interface{}{"c", "d"}))
+		})
+
+		It("does not let an element take the only partner of a later element", func() {
+			expected := []interface{}{
+				map[interface{}]interface{}{"a": 1},
+				map[interface{}]interface{}{"a": 1, "b": 2},
+			}
+			actual := []interface{}{
+				map[interface{}]interface{}{"a": 1, "b": 2},
+				map[interface{}]interface{}{"a": 1},
+			}
+
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{IgnoreOrder: true, IgnoreExtraKeys: true})
+			Expect(differences).To(BeEmpty())
+		})
+
+		It("pairs elements with embedded matchers so that every element finds a partner", func() {
+			expected := []interface{}{MatchRegexp("a.*"), "abc"}
+			actual := []interface{}{"abc", "axe"}
+
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{IgnoreOrder: true})
+			Expect(differences).To(BeEmpty())
+		})
+
+		It("only ignores the order of slices selected by IgnoreOrderPaths", func() {
+			expected := map[string]interface{}{
+				"releases":  []interface{}{"cf", "diego"},
+				"stemcells": []interface{}{"trusty", "xenial"},
+			}
+			actual := map[string]interface{}{
+				"releases":  []interface{}{"diego", "cf"},
+				"stemcells": []interface{}{"xenial", "trusty"},
+			}
+
+			pattern, err := deepequal.ParsePattern("[releases]")
+			Expect(err).NotTo(HaveOccurred())
+
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{
+				IgnoreOrderPaths: []deepequal.Pattern{pattern},
+			})
+			Expect(differences).To(HaveLen(2))
+			Expect(differences[0].(diff.MapNested).Key).To(Equal("stemcells"))
+			Expect(differences[1].(diff.MapNested).Key).To(Equal("stemcells"))
+		})
+
+		It("accepts patterns that select the elements of a slice", func() {
+			expected := map[string]interface{}{"releases": []interface{}{"cf", "diego"}}
+			actual := map[string]interface{}{"releases": []interface{}{"diego", "cf"}}
+
+			pattern, err := deepequal.ParsePattern("releases[*]")
+			Expect(err).NotTo(HaveOccurred())
+
+			differences := deepequal.CompareAll(expected, actual, deepequal.Options{
+				IgnoreOrderPaths: []deepequal.Pattern{pattern},
+			})
+			Expect(differences).To(BeEmpty())
+		})
+	})
 })
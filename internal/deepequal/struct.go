@@ -0,0 +1,101 @@
+package deepequal
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// structure compares the exported fields of two structs of the same type.
+// With a FieldTag, fields are reported under the keys they are encoded
+// with, like map keys, rather than under their Go names. Unexported fields
+// cannot be walked, so when a struct has some and every exported field
+// matches, the structs are compared with reflect.DeepEqual and reported as
+// a whole.
+func (c *comparison) structure(expectedStruct reflect.Value, actualStruct reflect.Value, path Path) []diff.Difference {
+	var differences []diff.Difference
+	unexported := false
+
+	for i := 0; i < actualStruct.NumField(); i++ {
+		if c.done() {
+			return differences
+		}
+
+		field := actualStruct.Type().Field(i)
+		if field.PkgPath != "" {
+			unexported = true
+			continue
+		}
+
+		expectedField, actualField := expectedStruct.Field(i).Interface(), actualStruct.Field(i).Interface()
+		if c.options.FieldTag == "" {
+			name := diff.FieldName(field.Name)
+			for _, difference := range c.compare(expectedField, actualField, path.child(name)) {
+				differences = append(differences, diff.StructNested{
+					Field:            name,
+					NestedDifference: difference,
+				})
+			}
+			continue
+		}
+
+		key, inline, skip := taggedName(field, c.options.FieldTag)
+		if skip {
+			continue
+		}
+
+		if inline {
+			differences = append(differences, c.compare(expectedField, actualField, path)...)
+			continue
+		}
+
+		for _, difference := range c.compare(expectedField, actualField, path.child(key)) {
+			differences = append(differences, diff.MapNested{
+				Key:              key,
+				NestedDifference: difference,
+			})
+		}
+	}
+
+	if !unexported || len(differences) > 0 || c.done() {
+		return differences
+	}
+
+	return c.primitive(expectedStruct.Interface(), actualStruct.Interface())
+}
+
+// pointer compares the values two pointers of the same type point to.
+// Pointers add no segment to the path, so a field reached through one is
+// reported as .Spec.Image whether or not Spec is a pointer.
+func (c *comparison) pointer(expectedPointer reflect.Value, actualPointer reflect.Value, path Path) []diff.Difference {
+	if expectedPointer.IsNil() || actualPointer.IsNil() || expectedPointer.Pointer() == actualPointer.Pointer() {
+		return c.primitive(expectedPointer.Interface(), actualPointer.Interface())
+	}
+
+	return c.compare(expectedPointer.Elem().Interface(), actualPointer.Elem().Interface(), path)
+}
+
+// taggedName returns the key a field is encoded under by a tag such as
+// `yaml:"name,omitempty"`, defaulting to the lowercased field name the way
+// yaml.v2 does. Inline fields are merged into the enclosing struct, and
+// fields tagged "-" are skipped.
+func taggedName(field reflect.StructField, tag string) (name string, inline bool, skip bool) {
+	value := field.Tag.Get(tag)
+	if value == "-" {
+		return "", false, true
+	}
+
+	options := strings.Split(value, ",")
+	for _, option := range options[1:] {
+		if option == "inline" {
+			return "", true, false
+		}
+	}
+
+	if options[0] != "" {
+		return options[0], false, false
+	}
+
+	return strings.ToLower(field.Name), false, false
+}
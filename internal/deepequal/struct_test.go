@@ -0,0 +1,115 @@
+package deepequal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+type image struct {
+	Name string
+	Tags []string
+}
+
+type digest struct {
+	Algorithm string
+	value     string
+}
+
+type taggedImage struct {
+	Name     string `yaml:"name"`
+	Registry string
+	Digest   digest `yaml:",inline"`
+	Comment  string `yaml:"-"`
+}
+
+var _ = Describe("Struct", func() {
+	It("compares exported fields and reports their names", func() {
+		equal, difference := deepequal.Compare(
+			image{Name: "nginx", Tags: []string{"1.19"}},
+			image{Name: "nginx", Tags: []string{"latest"}},
+		)
+		Expect(equal).To(BeFalse())
+		Expect(difference).To(Equal(diff.StructNested{
+			Field: "Tags",
+			NestedDifference: diff.SliceNested{
+				Index: 0,
+				NestedDifference: diff.PrimitiveValueMismatch{
+					ExpectedValue: "1.19",
+					ActualValue:   "latest",
+				},
+			},
+		}))
+	})
+
+	It("reports the struct itself when only unexported fields differ", func() {
+		equal, difference := deepequal.Compare(
+			digest{Algorithm: "sha256", value: "abc"},
+			digest{Algorithm: "sha256", value: "def"},
+		)
+		Expect(equal).To(BeFalse())
+		Expect(difference).To(Equal(diff.PrimitiveValueMismatch{
+			ExpectedValue: digest{Algorithm: "sha256", value: "abc"},
+			ActualValue:   digest{Algorithm: "sha256", value: "def"},
+		}))
+	})
+
+	It("follows pointers without adding to the path", func() {
+		equal, difference := deepequal.Compare(
+			&image{Name: "nginx"},
+			&image{Name: "redis"},
+		)
+		Expect(equal).To(BeFalse())
+		Expect(difference.Path()).To(Equal([]interface{}{diff.FieldName("Name")}))
+	})
+
+	It("compares arrays element by element", func() {
+		equal, difference := deepequal.Compare([2]int{1, 2}, [2]int{1, 3})
+		Expect(equal).To(BeFalse())
+		Expect(difference).To(Equal(diff.SliceNested{
+			Index: 1,
+			NestedDifference: diff.PrimitiveValueMismatch{
+				ExpectedValue: 2,
+				ActualValue:   3,
+			},
+		}))
+	})
+
+	It("compares arrays regardless of order when asked to", func() {
+		differences := deepequal.CompareAll([3]int{1, 2, 3}, [3]int{3, 1, 4}, deepequal.Options{IgnoreOrder: true})
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Kind()).To(Equal("unmatched elements"))
+	})
+
+	Context("with a FieldTag", func() {
+		options := deepequal.Options{FieldTag: "yaml"}
+
+		It("reports fields under their tagged keys like map keys", func() {
+			differences := deepequal.CompareAll(
+				taggedImage{Name: "nginx", Registry: "docker.io"},
+				taggedImage{Name: "redis", Registry: "quay.io"},
+				options,
+			)
+			Expect(differences).To(HaveLen(2))
+			Expect(differences[0]).To(Equal(diff.MapNested{
+				Key: "name",
+				NestedDifference: diff.PrimitiveValueMismatch{
+					ExpectedValue: "nginx",
+					ActualValue:   "redis",
+				},
+			}))
+			Expect(differences[1].Path()).To(Equal([]interface{}{"registry"}))
+		})
+
+		It("merges inline fields and skips fields tagged -", func() {
+			differences := deepequal.CompareAll(
+				taggedImage{Digest: digest{Algorithm: "sha256"}, Comment: "a"},
+				taggedImage{Digest: digest{Algorithm: "sha512"}, Comment: "b"},
+				options,
+			)
+			Expect(differences).To(HaveLen(1))
+			Expect(differences[0].Path()).To(Equal([]interface{}{"algorithm"}))
+		})
+	})
+})
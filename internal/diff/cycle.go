@@ -0,0 +1,78 @@
+package diff
+
+import "fmt"
+
+// CycleDetected is reported when a value refers back to a value that
+// encloses it, and the value on the other side is nil or empty, so that
+// comparing the two cannot settle whether the cycle matches.
+// ExpectedCycles and ActualCycles tell which sides refer back, and
+// ExpectedTarget and ActualTarget give the paths they refer back to.
+type CycleDetected struct {
+	leaf
+	ExpectedCycles bool
+	ExpectedTarget []interface{}
+	ActualCycles   bool
+	ActualTarget   []interface{}
+	ExpectedValue  interface{}
+	ActualValue    interface{}
+}
+
+func (CycleDetected) Kind() string {
+	return "cycle detected"
+}
+
+func (d CycleDetected) Description() string {
+	return fmt.Sprintf(`  cycle detected:
+    expected %s
+    actual %s`, cycleTarget(d.ExpectedCycles, d.ExpectedTarget), cycleTarget(d.ActualCycles, d.ActualTarget))
+}
+
+func (d CycleDetected) Expected() interface{} {
+	return d.ExpectedValue
+}
+
+func (d CycleDetected) Actual() interface{} {
+	return d.ActualValue
+}
+
+func cycleTarget(cycles bool, target []interface{}) string {
+	if !cycles {
+		return "does not refer back to an enclosing value"
+	}
+
+	if len(target) == 0 {
+		return "refers back to the root"
+	}
+
+	return "refers back to " + FormatPath(target)
+}
+
+// DepthExceeded is reported when two values nested deeper than MaxDepth
+// differ. They are not walked, so the difference is not localized further.
+type DepthExceeded struct {
+	leaf
+	MaxDepth      int
+	ExpectedValue interface{}
+	ActualValue   interface{}
+}
+
+func (DepthExceeded) Kind() string {
+	return "depth exceeded"
+}
+
+// Description leaves out the values, which may be too large or too deeply
+// nested to print.
+func (d DepthExceeded) Description() string {
+	return fmt.Sprintf(`  depth exceeded:
+    values of type
+        <%T>
+    differ below the maximum depth of %d`, d.ActualValue, d.MaxDepth)
+}
+
+func (d DepthExceeded) Expected() interface{} {
+	return d.ExpectedValue
+}
+
+func (d DepthExceeded) Actual() interface{} {
+	return d.ActualValue
+}
@@ -1,5 +1,75 @@
 package diff
 
-type Difference interface{}
+// Difference describes a mismatch between an expected and an actual value.
+// Nested differences lead to the mismatch through a map key, slice index or
+// element key; they report the path and delegate everything else to the
+// difference they wrap.
+type Difference interface {
+	// Path returns the map keys, slice indexes and element keys that lead
+	// to the mismatch.
+	Path() []interface{}
 
-type NoDifference struct{}
+	// Kind names the sort of mismatch, such as "value mismatch".
+	Kind() string
+
+	// Description explains the mismatch, indented to sit under an
+	// "error at [path]:" header.
+	Description() string
+
+	// Expected and Actual return the values involved in the mismatch.
+	// Which values they are depends on the Kind, and either may be nil.
+	Expected() interface{}
+	Actual() interface{}
+}
+
+// nested is implemented by differences that wrap another difference.
+type nested interface {
+	nestedDifference() Difference
+}
+
+// leaf provides the empty path of differences that describe the mismatch
+// themselves.
+type leaf struct{}
+
+func (leaf) Path() []interface{} {
+	return nil
+}
+
+type NoDifference struct {
+	leaf
+}
+
+func (NoDifference) Kind() string {
+	return ""
+}
+
+func (NoDifference) Description() string {
+	return ""
+}
+
+func (NoDifference) Expected() interface{} {
+	return nil
+}
+
+func (NoDifference) Actual() interface{} {
+	return nil
+}
+
+// Unwrap follows nested differences down to the difference that describes
+// the mismatch, and returns the path that leads to it.
+func Unwrap(difference Difference) ([]interface{}, Difference) {
+	path := difference.Path()
+
+	for {
+		wrapper, ok := difference.(nested)
+		if !ok {
+			return path, difference
+		}
+
+		difference = wrapper.nestedDifference()
+	}
+}
+
+func prepend(segment interface{}, difference Difference) []interface{} {
+	return append([]interface{}{segment}, difference.Path()...)
+}
@@ -0,0 +1,71 @@
+package diff_test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("Difference", func() {
+	var difference diff.Difference
+
+	BeforeEach(func() {
+		difference = diff.MapNested{
+			Key: "jobs",
+			NestedDifference: diff.SliceKeyedNested{
+				Key: diff.ElementKey{Field: "name", Value: "router"},
+				NestedDifference: diff.SliceNested{
+					Index: 1,
+					NestedDifference: diff.PrimitiveValueMismatch{
+						ExpectedValue: 443,
+						ActualValue:   80,
+					},
+				},
+			},
+		}
+	})
+
+	It("returns the path that leads to the mismatch", func() {
+		Expect(difference.Path()).To(Equal([]interface{}{
+			"jobs", diff.ElementKey{Field: "name", Value: "router"}, 1,
+		}))
+	})
+
+	It("delegates the kind, description and values to the nested difference", func() {
+		Expect(difference.Kind()).To(Equal("value mismatch"))
+		Expect(difference.Description()).To(HavePrefix("  value mismatch:\n    Expected\n        <int> 80"))
+		Expect(difference.Expected()).To(Equal(443))
+		Expect(difference.Actual()).To(Equal(80))
+	})
+
+	It("describes each side of a mismatch", func() {
+		missing := diff.MapMissingKey{
+			MissingKey: "port",
+			AllKeys:    []reflect.Value{reflect.ValueOf("host")},
+		}
+		Expect(missing.Kind()).To(Equal("missing key"))
+		Expect(missing.Expected()).To(Equal("port"))
+		Expect(missing.Actual()).To(BeNil())
+
+		extra := diff.SliceExtraElements{
+			ExtraElements: reflect.ValueOf([]interface{}{"c"}),
+			AllElements:   reflect.ValueOf([]interface{}{"a", "b", "c"}),
+		}
+		Expect(extra.Kind()).To(Equal("extra elements"))
+		Expect(extra.Expected()).To(BeNil())
+		Expect(extra.Actual()).To(Equal([]interface{}{"c"}))
+	})
+
+	Describe("Unwrap", func() {
+		It("returns the path and the innermost difference", func() {
+			path, leaf := diff.Unwrap(difference)
+			Expect(path).To(Equal(difference.Path()))
+			Expect(leaf).To(Equal(diff.PrimitiveValueMismatch{
+				ExpectedValue: 443,
+				ActualValue:   80,
+			}))
+		})
+	})
+})
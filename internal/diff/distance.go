@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// editDistance counts the characters that must be inserted, deleted or
+// replaced to turn a into b.
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+func minimum(first int, rest ...int) int {
+	for _, value := range rest {
+		if value < first {
+			first = value
+		}
+	}
+
+	return first
+}
+
+// closestFirst orders keys by how few edits turn them into key, and keys
+// that are as close by how they print.
+func closestFirst(keys []reflect.Value, key interface{}) []reflect.Value {
+	target := fmt.Sprintf("%+v", key)
+
+	type candidate struct {
+		value    reflect.Value
+		text     string
+		distance int
+	}
+
+	candidates := make([]candidate, len(keys))
+	for i, value := range keys {
+		text := fmt.Sprintf("%+v", value)
+		candidates[i] = candidate{value: value, text: text, distance: editDistance(text, target)}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].text < candidates[j].text
+	})
+
+	sorted := make([]reflect.Value, len(candidates))
+	for i, candidate := range candidates {
+		sorted[i] = candidate.value
+	}
+
+	return sorted
+}
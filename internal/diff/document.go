@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocumentKey identifies a document of a YAML stream by its position, or by
+// the values of its identity fields, along with where it appears in each
+// stream. An index is -1 when the document is absent from that stream.
+type DocumentKey struct {
+	Name          string
+	ExpectedIndex int
+	ActualIndex   int
+}
+
+func (key DocumentKey) String() string {
+	return "document " + key.Name
+}
+
+type DocumentNested struct {
+	Document         DocumentKey
+	NestedDifference Difference
+}
+
+func (d DocumentNested) Path() []interface{} {
+	return prepend(d.Document, d.NestedDifference)
+}
+
+func (d DocumentNested) Kind() string {
+	return d.NestedDifference.Kind()
+}
+
+func (d DocumentNested) Description() string {
+	return d.NestedDifference.Description()
+}
+
+func (d DocumentNested) Expected() interface{} {
+	return d.NestedDifference.Expected()
+}
+
+func (d DocumentNested) Actual() interface{} {
+	return d.NestedDifference.Actual()
+}
+
+func (d DocumentNested) nestedDifference() Difference {
+	return d.NestedDifference
+}
+
+type DocumentExtra struct {
+	leaf
+	ExtraDocument DocumentKey
+	AllDocuments  []DocumentKey
+}
+
+func (DocumentExtra) Kind() string {
+	return "extra document"
+}
+
+func (d DocumentExtra) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d DocumentExtra) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  extra document found:
+    Expected
+        %s
+    not to contain
+        %s`, documentNames(d.AllDocuments), style.actual(d.ExtraDocument.String()))
+}
+
+func (DocumentExtra) Expected() interface{} {
+	return nil
+}
+
+func (d DocumentExtra) Actual() interface{} {
+	return d.ExtraDocument
+}
+
+type DocumentMissing struct {
+	leaf
+	MissingDocument DocumentKey
+	AllDocuments    []DocumentKey
+}
+
+func (DocumentMissing) Kind() string {
+	return "missing document"
+}
+
+func (d DocumentMissing) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d DocumentMissing) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  missing document:
+    Expected
+        %s
+    to contain
+        %s`, documentNames(d.AllDocuments), style.expected(d.MissingDocument.String()))
+}
+
+func (d DocumentMissing) Expected() interface{} {
+	return d.MissingDocument
+}
+
+func (DocumentMissing) Actual() interface{} {
+	return nil
+}
+
+func documentNames(documents []DocumentKey) string {
+	var names []string
+	for _, document := range documents {
+		names = append(names, document.String())
+	}
+
+	return "[" + strings.Join(names, ", ") + "]"
+}
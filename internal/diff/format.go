@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FormatPath prints struct fields as .Name and every other segment in
+// brackets, as in .Spec.Containers[2][image].
+func FormatPath(path []interface{}) string {
+	var segments []string
+	for _, segment := range path {
+		if field, ok := segment.(FieldName); ok {
+			segments = append(segments, "."+string(field))
+			continue
+		}
+
+		segments = append(segments, fmt.Sprintf("[%+v]", segment))
+	}
+
+	return strings.Join(segments, "")
+}
+
+func SliceOfValues(values []reflect.Value) string {
+	return Limits{}.values(values)
+}
+
+func SliceAsValue(values reflect.Value) string {
+	return Limits{}.sliceValue(values)
+}
+
+func indent(text string, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+
+	return strings.Join(lines, "\n")
+}
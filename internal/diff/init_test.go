@@ -0,0 +1,13 @@
+package diff_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGomegaMatchers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "internal/diff")
+}
@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits bound how much of each value a description prints. A zero limit
+// is no limit.
+type Limits struct {
+	// MaxElements caps the elements of slices and maps, the keys listed
+	// next to a missing or extra key, and the changed lines of multi-line
+	// strings. The rest are summarized as "... N more".
+	MaxElements int
+
+	// MaxStringLength caps the characters printed of each string.
+	MaxStringLength int
+
+	// MaxDepth caps how deeply slices and maps nested within a value are
+	// printed.
+	MaxDepth int
+}
+
+func (limits Limits) none() bool {
+	return limits == Limits{}
+}
+
+// value prints v with its type, as in <string> a.
+func (limits Limits) value(v interface{}) string {
+	return fmt.Sprintf("<%T> %s", v, limits.format(reflect.ValueOf(v), 0))
+}
+
+// values prints a list of values with their types, as SliceOfValues does.
+func (limits Limits) values(values []reflect.Value) string {
+	var printed []string
+	for i, value := range values {
+		if limits.MaxElements > 0 && i == limits.MaxElements {
+			printed = append(printed, fmt.Sprintf("... %d more", len(values)-i))
+			break
+		}
+
+		printed = append(printed, fmt.Sprintf("<%T> %s", value.Interface(), limits.format(value, 0)))
+	}
+
+	return "[" + strings.Join(printed, ", ") + "]"
+}
+
+// keys lists the keys next to a missing or extra key. When there are more
+// than MaxElements, the keys closest to key are the ones listed.
+func (limits Limits) keys(keys []reflect.Value, key interface{}) string {
+	if limits.MaxElements > 0 && len(keys) > limits.MaxElements {
+		keys = closestFirst(keys, key)
+	}
+
+	return limits.values(keys)
+}
+
+// sliceValue prints the elements of a slice with their types, as
+// SliceAsValue does.
+func (limits Limits) sliceValue(slice reflect.Value) string {
+	values := make([]reflect.Value, slice.Len())
+	for i := range values {
+		values[i] = slice.Index(i)
+	}
+
+	return limits.values(values)
+}
+
+// format prints a value as %+v does, within the limits.
+func (limits Limits) format(value reflect.Value, depth int) string {
+	if !value.IsValid() {
+		return "<nil>"
+	}
+	if limits.none() || !value.CanInterface() {
+		return fmt.Sprintf("%+v", value)
+	}
+	if value.Type().Implements(stringerType) || value.Type().Implements(errorType) {
+		return limits.truncate(fmt.Sprintf("%+v", value))
+	}
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return "<nil>"
+		}
+		return limits.format(value.Elem(), depth)
+
+	case reflect.String:
+		return limits.truncate(value.String())
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return "[]"
+		}
+		if limits.MaxDepth > 0 && depth >= limits.MaxDepth {
+			return fmt.Sprintf("[... %d more]", value.Len())
+		}
+
+		var elements []string
+		for i := 0; i < value.Len(); i++ {
+			if limits.MaxElements > 0 && i == limits.MaxElements {
+				elements = append(elements, fmt.Sprintf("... %d more", value.Len()-i))
+				break
+			}
+
+			elements = append(elements, limits.format(value.Index(i), depth+1))
+		}
+
+		return "[" + strings.Join(elements, " ") + "]"
+
+	case reflect.Map:
+		if limits.MaxDepth > 0 && depth >= limits.MaxDepth {
+			return fmt.Sprintf("map[... %d more]", value.Len())
+		}
+
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%+v", keys[i]) < fmt.Sprintf("%+v", keys[j])
+		})
+
+		var entries []string
+		for i, key := range keys {
+			if limits.MaxElements > 0 && i == limits.MaxElements {
+				entries = append(entries, fmt.Sprintf("... %d more", len(keys)-i))
+				break
+			}
+
+			entries = append(entries, limits.format(key, depth+1)+":"+limits.format(value.MapIndex(key), depth+1))
+		}
+
+		return "map[" + strings.Join(entries, " ") + "]"
+
+	default:
+		return fmt.Sprintf("%+v", value)
+	}
+}
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// truncate cuts text down to MaxStringLength characters.
+func (limits Limits) truncate(text string) string {
+	if limits.MaxStringLength <= 0 {
+		return text
+	}
+
+	length := utf8.RuneCountInString(text)
+	if length <= limits.MaxStringLength {
+		return text
+	}
+
+	var end, count int
+	for end = range text {
+		if count == limits.MaxStringLength {
+			break
+		}
+		count++
+	}
+
+	return fmt.Sprintf("%s... %d more", text[:end], length-limits.MaxStringLength)
+}
+
+// truncates reports whether MaxStringLength cuts text short.
+func (limits Limits) truncates(text string) bool {
+	return limits.MaxStringLength > 0 && utf8.RuneCountInString(text) > limits.MaxStringLength
+}
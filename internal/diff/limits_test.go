@@ -0,0 +1,96 @@
+package diff_test
+
+import (
+	"fmt"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("Limits", func() {
+	var style diff.Style
+
+	BeforeEach(func() {
+		style = diff.Style{}
+	})
+
+	It("prints everything without limits", func() {
+		d := diff.PrimitiveValueMismatch{
+			ExpectedValue: []interface{}{1, 2, 3},
+			ActualValue:   []interface{}{1, 2},
+		}
+
+		Expect(d.StyledDescription(style)).To(Equal(d.Description()))
+		Expect(d.Description()).To(ContainSubstring("<[]interface {}> [1 2 3]"))
+	})
+
+	It("summarizes the elements past MaxElements", func() {
+		style.Limits.MaxElements = 2
+		d := diff.SliceExtraElements{
+			ExtraElements: reflect.ValueOf([]int{4, 5, 6, 7, 8}),
+			AllElements:   reflect.ValueOf([]int{1, 2, 3, 4, 5, 6, 7, 8}),
+		}
+
+		Expect(d.StyledDescription(style)).To(Equal(`  extra elements found:
+    Expected
+        [<int> 1, <int> 2, ... 6 more]
+    not to contain elements
+        [<int> 4, <int> 5, ... 3 more]`))
+	})
+
+	It("summarizes the elements and entries of nested values", func() {
+		style.Limits.MaxElements = 2
+		d := diff.PrimitiveValueMismatch{
+			ExpectedValue: map[string]interface{}{"c": 3, "a": []interface{}{1, 2, 3}, "b": 2},
+			ActualValue:   nil,
+		}
+
+		Expect(d.StyledDescription(style)).To(ContainSubstring("<map[string]interface {}> map[a:[1 2 ... 1 more] b:2 ... 1 more]"))
+	})
+
+	It("cuts strings down to MaxStringLength characters", func() {
+		style.Limits.MaxStringLength = 5
+		d := diff.MapMissingKey{
+			MissingKey: "certificate",
+			AllKeys:    []reflect.Value{reflect.ValueOf("name")},
+		}
+
+		Expect(d.StyledDescription(style)).To(ContainSubstring("[<string> name]"))
+		Expect(d.StyledDescription(style)).To(ContainSubstring("<string> certi... 6 more"))
+	})
+
+	It("elides values nested deeper than MaxDepth", func() {
+		style.Limits.MaxDepth = 1
+		d := diff.PrimitiveValueMismatch{
+			ExpectedValue: []interface{}{1, []interface{}{2, 3}, map[string]interface{}{"a": 4}},
+			ActualValue:   nil,
+		}
+
+		Expect(d.StyledDescription(style)).To(ContainSubstring("[1 [... 2 more] map[... 1 more]]"))
+	})
+
+	It("lists the keys closest to a missing key when there are too many", func() {
+		style.Limits.MaxElements = 3
+		var keys []reflect.Value
+		for i := 0; i < 40; i++ {
+			keys = append(keys, reflect.ValueOf(fmt.Sprintf("property_%02d", i)))
+		}
+		keys = append(keys, reflect.ValueOf("instance_group"))
+
+		d := diff.MapMissingKey{MissingKey: "instance_groups", AllKeys: keys}
+
+		Expect(d.StyledDescription(style)).To(ContainSubstring("[<string> instance_group, <string> property_00, <string> property_01, ... 38 more]"))
+	})
+
+	It("lists changed lines up to MaxElements", func() {
+		style.Limits.MaxElements = 2
+		d := diff.PrimitiveValueMismatch{
+			ExpectedValue: "a\nb\nc",
+			ActualValue:   "x\ny\nz",
+		}
+
+		Expect(d.StyledDescription(style)).To(HaveSuffix("        -a\n        -b\n        ... 4 more"))
+	})
+})
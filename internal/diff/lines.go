@@ -0,0 +1,55 @@
+package diff
+
+// Line is a line of a line-by-line comparison of two texts. Kind is ' ' for
+// a line both texts share, '-' for a line only the expected text has and
+// '+' for a line only the actual text has.
+type Line struct {
+	Kind byte
+	Text string
+
+	// ExpectedLine and ActualLine number the line from 1 on each side. On
+	// the side a line is missing from, they number the line that follows.
+	ExpectedLine int
+	ActualLine   int
+}
+
+// AlignLines aligns the two texts on their longest common subsequence of
+// lines.
+func AlignLines(expected []string, actual []string) []Line {
+	lengths := make([][]int, len(expected)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(actual)+1)
+	}
+
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			switch {
+			case expected[i] == actual[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			lines = append(lines, Line{Kind: ' ', Text: expected[i], ExpectedLine: i + 1, ActualLine: j + 1})
+			i++
+			j++
+		case j == len(actual) || i < len(expected) && lengths[i+1][j] >= lengths[i][j+1]:
+			lines = append(lines, Line{Kind: '-', Text: expected[i], ExpectedLine: i + 1, ActualLine: j + 1})
+			i++
+		default:
+			lines = append(lines, Line{Kind: '+', Text: actual[j], ExpectedLine: i + 1, ActualLine: j + 1})
+			j++
+		}
+	}
+
+	return lines
+}
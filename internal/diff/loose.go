@@ -0,0 +1,44 @@
+package diff
+
+import "fmt"
+
+// LooseMatch records scalars that are only equal by their canonical string
+// form, such as 1.0 and "1.0". It is a warning rather than a mismatch, and
+// carries its own path instead of being nested.
+type LooseMatch struct {
+	Location      []interface{}
+	ExpectedValue interface{}
+	ActualValue   interface{}
+	Form          string
+}
+
+func (d LooseMatch) Path() []interface{} {
+	return d.Location
+}
+
+func (LooseMatch) Kind() string {
+	return "loose match"
+}
+
+func (d LooseMatch) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d LooseMatch) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  loose match:
+    Expected
+        %s
+    matched
+        %s
+    only by its string form %q`,
+		style.actual(style.Limits.value(d.ActualValue)),
+		style.expected(style.Limits.value(d.ExpectedValue)), d.Form)
+}
+
+func (d LooseMatch) Expected() interface{} {
+	return d.ExpectedValue
+}
+
+func (d LooseMatch) Actual() interface{} {
+	return d.ActualValue
+}
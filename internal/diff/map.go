@@ -1,18 +1,95 @@
 package diff
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 type MapNested struct {
 	Key              interface{}
 	NestedDifference Difference
 }
 
+func (d MapNested) Path() []interface{} {
+	return prepend(d.Key, d.NestedDifference)
+}
+
+func (d MapNested) Kind() string {
+	return d.NestedDifference.Kind()
+}
+
+func (d MapNested) Description() string {
+	return d.NestedDifference.Description()
+}
+
+func (d MapNested) Expected() interface{} {
+	return d.NestedDifference.Expected()
+}
+
+func (d MapNested) Actual() interface{} {
+	return d.NestedDifference.Actual()
+}
+
+func (d MapNested) nestedDifference() Difference {
+	return d.NestedDifference
+}
+
 type MapExtraKey struct {
+	leaf
 	ExtraKey interface{}
 	AllKeys  []reflect.Value
 }
 
+func (MapExtraKey) Kind() string {
+	return "extra key"
+}
+
+func (d MapExtraKey) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d MapExtraKey) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  extra key found:
+    Expected
+        %s
+    not to contain key
+        %s`, style.Limits.keys(d.AllKeys, d.ExtraKey), style.actual(style.Limits.value(d.ExtraKey)))
+}
+
+func (MapExtraKey) Expected() interface{} {
+	return nil
+}
+
+func (d MapExtraKey) Actual() interface{} {
+	return d.ExtraKey
+}
+
 type MapMissingKey struct {
+	leaf
 	MissingKey interface{}
 	AllKeys    []reflect.Value
 }
+
+func (MapMissingKey) Kind() string {
+	return "missing key"
+}
+
+func (d MapMissingKey) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d MapMissingKey) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  missing key:
+    Expected
+        %s
+    to contain key
+        %s`, style.Limits.keys(d.AllKeys, d.MissingKey), style.expected(style.Limits.value(d.MissingKey)))
+}
+
+func (d MapMissingKey) Expected() interface{} {
+	return d.MissingKey
+}
+
+func (MapMissingKey) Actual() interface{} {
+	return nil
+}
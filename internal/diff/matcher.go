@@ -0,0 +1,38 @@
+package diff
+
+import "fmt"
+
+// MatcherFailure is reported when a gomega matcher embedded in the expected
+// value rejects the actual value, or returns an error.
+type MatcherFailure struct {
+	leaf
+	FailureMessage string
+	Error          error
+}
+
+func (MatcherFailure) Kind() string {
+	return "matcher failure"
+}
+
+func (d MatcherFailure) Description() string {
+	if d.Error != nil {
+		return fmt.Sprintf(`  matcher error:
+%s`, indent(d.Error.Error(), "    "))
+	}
+
+	return fmt.Sprintf(`  matcher failed:
+%s`, indent(d.FailureMessage, "    "))
+}
+
+func (MatcherFailure) Expected() interface{} {
+	return nil
+}
+
+// Actual returns the error of the matcher, or its failure message.
+func (d MatcherFailure) Actual() interface{} {
+	if d.Error != nil {
+		return d.Error
+	}
+
+	return d.FailureMessage
+}
@@ -1,13 +1,81 @@
 package diff
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 type PrimitiveValueMismatch struct {
+	leaf
 	ExpectedValue interface{}
 	ActualValue   interface{}
 }
 
+func (PrimitiveValueMismatch) Kind() string {
+	return "value mismatch"
+}
+
+func (d PrimitiveValueMismatch) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d PrimitiveValueMismatch) StyledDescription(style Style) string {
+	expected := style.Limits.format(reflect.ValueOf(d.ExpectedValue), 0)
+	actual := style.Limits.format(reflect.ValueOf(d.ActualValue), 0)
+	var location string
+	if expectedString, ok := d.ExpectedValue.(string); ok {
+		if actualString, ok := d.ActualValue.(string); ok {
+			expected, actual = style.highlightStrings(expectedString, actualString)
+			location = style.stringDifference(expectedString, actualString)
+		}
+	}
+
+	return fmt.Sprintf(`  value mismatch:
+    Expected
+        %s
+    to equal
+        %s%s`,
+		style.actual(fmt.Sprintf("<%T> %s", d.ActualValue, actual)),
+		style.expected(fmt.Sprintf("<%T> %s", d.ExpectedValue, expected)),
+		location)
+}
+
+func (d PrimitiveValueMismatch) Expected() interface{} {
+	return d.ExpectedValue
+}
+
+func (d PrimitiveValueMismatch) Actual() interface{} {
+	return d.ActualValue
+}
+
 type PrimitiveTypeMismatch struct {
+	leaf
 	ExpectedType reflect.Type
 	ActualValue  interface{}
 }
+
+func (PrimitiveTypeMismatch) Kind() string {
+	return "type mismatch"
+}
+
+func (d PrimitiveTypeMismatch) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d PrimitiveTypeMismatch) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  type mismatch:
+    Expected
+        %s
+    to be of type
+        %s`,
+		style.actual(style.Limits.value(d.ActualValue)),
+		style.expected(fmt.Sprintf("<%s>", d.ExpectedType)))
+}
+
+func (d PrimitiveTypeMismatch) Expected() interface{} {
+	return d.ExpectedType
+}
+
+func (d PrimitiveTypeMismatch) Actual() interface{} {
+	return d.ActualValue
+}
@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Report is the machine-readable form of a list of differences, meant to be
+// encoded as JSON.
+type Report struct {
+	Differences []ReportEntry `json:"differences"`
+}
+
+// ReportEntry is the machine-readable form of a difference. Map keys and
+// slice indexes in the path are JSON values, while struct fields, element
+// keys and documents are objects such as {"struct_field": "Name"},
+// {"element_key": {"field": "name", "value": "router"}} and
+// {"document": "kind=Service"}. Values that JSON cannot represent, such as
+// types, are written as they print.
+type ReportEntry struct {
+	Path     []interface{} `json:"path"`
+	Kind     string        `json:"kind"`
+	Expected interface{}   `json:"expected"`
+	Actual   interface{}   `json:"actual"`
+
+	// ExpectedPosition and ActualPosition locate the values in the sources
+	// they were read from, when those are known.
+	ExpectedPosition *Position `json:"expected_position,omitempty"`
+	ActualPosition   *Position `json:"actual_position,omitempty"`
+}
+
+// Position is where a value appears in its source. Anchor names the YAML
+// anchor the value was inherited from, if any.
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Anchor string `json:"anchor,omitempty"`
+}
+
+// NewReport builds the report of differences. locate, when not nil,
+// returns the positions of the expected and actual values of a difference,
+// either of which may be nil.
+func NewReport(differences []Difference, locate func(Difference) (*Position, *Position)) Report {
+	report := Report{Differences: []ReportEntry{}}
+	for _, difference := range differences {
+		entry := NewReportEntry(difference.Path(), difference.Kind(), difference.Expected(), difference.Actual())
+		if locate != nil {
+			entry.ExpectedPosition, entry.ActualPosition = locate(difference)
+		}
+
+		report.Differences = append(report.Differences, entry)
+	}
+
+	return report
+}
+
+// NewReportEntry converts a path and the values of a difference to forms
+// that encoding/json can write.
+func NewReportEntry(path []interface{}, kind string, expected interface{}, actual interface{}) ReportEntry {
+	segments := make([]interface{}, len(path))
+	for i, segment := range path {
+		segments[i] = jsonValue(segment, map[uintptr]bool{})
+	}
+
+	return ReportEntry{
+		Path:     segments,
+		Kind:     kind,
+		Expected: jsonValue(expected, map[uintptr]bool{}),
+		Actual:   jsonValue(actual, map[uintptr]bool{}),
+	}
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// jsonValue converts a value to one that encoding/json can write, turning
+// maps with keys other than strings into objects keyed by how the keys
+// print. seen holds the maps, slices and pointers being converted, so that
+// a value that refers back to one of them is written as "<cycle>".
+func jsonValue(v interface{}, seen map[uintptr]bool) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case FieldName:
+		return map[string]interface{}{"struct_field": string(v)}
+	case ElementKey:
+		return map[string]interface{}{"element_key": map[string]interface{}{
+			"field": jsonValue(v.Field, seen),
+			"value": jsonValue(v.Value, seen),
+		}}
+	case DocumentKey:
+		return map[string]interface{}{"document": v.Name}
+	case reflect.Type:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Type().Implements(marshalerType) {
+		if _, err := json.Marshal(v); err == nil {
+			return v
+		}
+		return fmt.Sprintf("%+v", v)
+	}
+
+	switch value.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v
+
+	case reflect.Float32, reflect.Float64:
+		if float := value.Float(); math.IsNaN(float) || math.IsInf(float, 0) {
+			return fmt.Sprintf("%+v", v)
+		}
+		return v
+
+	case reflect.Map, reflect.Slice, reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+
+		pointer := value.Pointer()
+		if seen[pointer] {
+			return "<cycle>"
+		}
+		seen[pointer] = true
+		defer delete(seen, pointer)
+
+		switch value.Kind() {
+		case reflect.Map:
+			object := map[string]interface{}{}
+			for _, key := range value.MapKeys() {
+				object[fmt.Sprintf("%+v", key)] = jsonValue(value.MapIndex(key).Interface(), seen)
+			}
+			return object
+
+		case reflect.Slice:
+			return jsonElements(value, seen)
+
+		default:
+			return jsonValue(value.Elem().Interface(), seen)
+		}
+
+	case reflect.Array:
+		return jsonElements(value, seen)
+
+	case reflect.Struct:
+		if _, err := json.Marshal(v); err == nil {
+			return v
+		}
+		return fmt.Sprintf("%+v", v)
+
+	default:
+		return fmt.Sprintf("%+v", v)
+	}
+}
+
+func jsonElements(value reflect.Value, seen map[uintptr]bool) []interface{} {
+	elements := make([]interface{}, value.Len())
+	for i := range elements {
+		elements[i] = jsonValue(value.Index(i).Interface(), seen)
+	}
+
+	return elements
+}
@@ -0,0 +1,85 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("Report", func() {
+	encode := func(value interface{}) string {
+		data, err := json.Marshal(value)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	It("writes the path as an array of segments", func() {
+		report := diff.NewReport([]diff.Difference{
+			diff.DocumentNested{
+				Document: diff.DocumentKey{Name: "kind=Service"},
+				NestedDifference: diff.StructNested{
+					Field: "Spec",
+					NestedDifference: diff.SliceKeyedNested{
+						Key: diff.ElementKey{Field: "name", Value: "router"},
+						NestedDifference: diff.SliceNested{
+							Index:            1,
+							NestedDifference: diff.PrimitiveValueMismatch{ExpectedValue: 443, ActualValue: 80},
+						},
+					},
+				},
+			},
+		}, nil)
+
+		Expect(encode(report)).To(MatchJSON(`{"differences": [{
+			"path": [
+				{"document": "kind=Service"},
+				{"struct_field": "Spec"},
+				{"element_key": {"field": "name", "value": "router"}},
+				1
+			],
+			"kind": "value mismatch",
+			"expected": 443,
+			"actual": 80
+		}]}`))
+	})
+
+	It("adds the positions that locate returns", func() {
+		report := diff.NewReport([]diff.Difference{
+			diff.MapMissingKey{MissingKey: "port"},
+		}, func(diff.Difference) (*diff.Position, *diff.Position) {
+			return &diff.Position{File: "expected.yml", Line: 3, Column: 1, Anchor: "defaults"}, nil
+		})
+
+		Expect(encode(report)).To(MatchJSON(`{"differences": [{
+			"path": [],
+			"kind": "missing key",
+			"expected": "port",
+			"actual": null,
+			"expected_position": {"file": "expected.yml", "line": 3, "column": 1, "anchor": "defaults"}
+		}]}`))
+	})
+
+	It("writes an empty array without differences", func() {
+		Expect(encode(diff.NewReport(nil, nil))).To(MatchJSON(`{"differences": []}`))
+	})
+
+	It("converts values that JSON cannot represent", func() {
+		cyclic := map[string]interface{}{}
+		cyclic["self"] = cyclic
+
+		entry := diff.NewReportEntry(nil, "value mismatch",
+			map[interface{}]interface{}{1: []interface{}{"a", math.NaN()}, "self": cyclic},
+			reflect.TypeOf(0))
+
+		Expect(encode(entry)).To(MatchJSON(`{
+			"path": [],
+			"kind": "value mismatch",
+			"expected": {"1": ["a", "NaN"], "self": {"self": "<cycle>"}},
+			"actual": "int"
+		}`))
+	})
+})
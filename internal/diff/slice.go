@@ -1,18 +1,250 @@
 package diff
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 type SliceNested struct {
 	Index            int
 	NestedDifference Difference
 }
 
+func (d SliceNested) Path() []interface{} {
+	return prepend(d.Index, d.NestedDifference)
+}
+
+func (d SliceNested) Kind() string {
+	return d.NestedDifference.Kind()
+}
+
+func (d SliceNested) Description() string {
+	return d.NestedDifference.Description()
+}
+
+func (d SliceNested) Expected() interface{} {
+	return d.NestedDifference.Expected()
+}
+
+func (d SliceNested) Actual() interface{} {
+	return d.NestedDifference.Actual()
+}
+
+func (d SliceNested) nestedDifference() Difference {
+	return d.NestedDifference
+}
+
 type SliceExtraElements struct {
+	leaf
 	ExtraElements reflect.Value
 	AllElements   reflect.Value
 }
 
+func (SliceExtraElements) Kind() string {
+	return "extra elements"
+}
+
+func (d SliceExtraElements) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d SliceExtraElements) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  extra elements found:
+    Expected
+        %s
+    not to contain elements
+        %s`, style.Limits.sliceValue(d.AllElements), style.actual(style.Limits.sliceValue(d.ExtraElements)))
+}
+
+func (SliceExtraElements) Expected() interface{} {
+	return nil
+}
+
+func (d SliceExtraElements) Actual() interface{} {
+	return d.ExtraElements.Interface()
+}
+
 type SliceMissingElements struct {
+	leaf
 	MissingElements reflect.Value
 	AllElements     reflect.Value
 }
+
+func (SliceMissingElements) Kind() string {
+	return "missing elements"
+}
+
+func (d SliceMissingElements) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d SliceMissingElements) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  missing elements:
+    Expected
+        %s
+    to contain elements
+        %s`, style.Limits.sliceValue(d.AllElements), style.expected(style.Limits.sliceValue(d.MissingElements)))
+}
+
+func (d SliceMissingElements) Expected() interface{} {
+	return d.MissingElements.Interface()
+}
+
+func (SliceMissingElements) Actual() interface{} {
+	return nil
+}
+
+// SliceUnorderedMismatch describes slices compared without regard to order.
+// MissingElements are expected elements with no equal actual element, and
+// ExtraElements are actual elements with no equal expected element.
+type SliceUnorderedMismatch struct {
+	leaf
+	MissingElements reflect.Value
+	ExtraElements   reflect.Value
+}
+
+func (SliceUnorderedMismatch) Kind() string {
+	return "unmatched elements"
+}
+
+func (d SliceUnorderedMismatch) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d SliceUnorderedMismatch) StyledDescription(style Style) string {
+	description := "  unmatched elements (ignoring order):"
+
+	if d.MissingElements.Len() > 0 {
+		description += fmt.Sprintf(`
+    Expected elements not found in actual
+        %s`, style.expected(style.Limits.sliceValue(d.MissingElements)))
+	}
+
+	if d.ExtraElements.Len() > 0 {
+		description += fmt.Sprintf(`
+    Actual elements not found in expected
+        %s`, style.actual(style.Limits.sliceValue(d.ExtraElements)))
+	}
+
+	return description
+}
+
+func (d SliceUnorderedMismatch) Expected() interface{} {
+	return d.MissingElements.Interface()
+}
+
+func (d SliceUnorderedMismatch) Actual() interface{} {
+	return d.ExtraElements.Interface()
+}
+
+// ElementKey identifies a slice element by the value of one of its fields,
+// such as name=router.
+type ElementKey struct {
+	Field interface{}
+	Value interface{}
+}
+
+func (key ElementKey) String() string {
+	return fmt.Sprintf("%+v=%+v", key.Field, key.Value)
+}
+
+type SliceKeyedNested struct {
+	Key              ElementKey
+	NestedDifference Difference
+}
+
+func (d SliceKeyedNested) Path() []interface{} {
+	return prepend(d.Key, d.NestedDifference)
+}
+
+func (d SliceKeyedNested) Kind() string {
+	return d.NestedDifference.Kind()
+}
+
+func (d SliceKeyedNested) Description() string {
+	return d.NestedDifference.Description()
+}
+
+func (d SliceKeyedNested) Expected() interface{} {
+	return d.NestedDifference.Expected()
+}
+
+func (d SliceKeyedNested) Actual() interface{} {
+	return d.NestedDifference.Actual()
+}
+
+func (d SliceKeyedNested) nestedDifference() Difference {
+	return d.NestedDifference
+}
+
+type SliceExtraKeyedElement struct {
+	leaf
+	ExtraKey ElementKey
+	AllKeys  []ElementKey
+}
+
+func (SliceExtraKeyedElement) Kind() string {
+	return "extra element"
+}
+
+func (d SliceExtraKeyedElement) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d SliceExtraKeyedElement) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  extra element found:
+    Expected
+        %s
+    not to contain element with %+v
+        %s`, style.keyValues(d.AllKeys, d.ExtraKey), d.ExtraKey.Field,
+		style.actual(style.Limits.value(d.ExtraKey.Value)))
+}
+
+func (SliceExtraKeyedElement) Expected() interface{} {
+	return nil
+}
+
+func (d SliceExtraKeyedElement) Actual() interface{} {
+	return d.ExtraKey
+}
+
+type SliceMissingKeyedElement struct {
+	leaf
+	MissingKey ElementKey
+	AllKeys    []ElementKey
+}
+
+func (SliceMissingKeyedElement) Kind() string {
+	return "missing element"
+}
+
+func (d SliceMissingKeyedElement) Description() string {
+	return d.StyledDescription(Plain)
+}
+
+func (d SliceMissingKeyedElement) StyledDescription(style Style) string {
+	return fmt.Sprintf(`  missing element:
+    Expected
+        %s
+    to contain element with %+v
+        %s`, style.keyValues(d.AllKeys, d.MissingKey), d.MissingKey.Field,
+		style.expected(style.Limits.value(d.MissingKey.Value)))
+}
+
+func (d SliceMissingKeyedElement) Expected() interface{} {
+	return d.MissingKey
+}
+
+func (SliceMissingKeyedElement) Actual() interface{} {
+	return nil
+}
+
+// keyValues lists the values of keys, as Limits.keys does.
+func (style Style) keyValues(keys []ElementKey, key ElementKey) string {
+	var values []reflect.Value
+	for _, key := range keys {
+		values = append(values, reflect.ValueOf(key.Value))
+	}
+
+	return style.Limits.keys(values, key.Value)
+}
@@ -1,16 +1,16 @@
-package prettyprint_test
+package diff_test
 
 import (
 	"reflect"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
 )
 
 var _ = Describe("SliceAsValue", func() {
 	It("returns a string representation of the slice", func() {
 		sliceAsValue := reflect.ValueOf([]string{"a", "b"})
-		Expect(prettyprint.SliceAsValue(sliceAsValue)).To(Equal("[<string> a, <string> b]"))
+		Expect(diff.SliceAsValue(sliceAsValue)).To(Equal("[<string> a, <string> b]"))
 	})
 })
@@ -1,16 +1,16 @@
-package prettyprint_test
+package diff_test
 
 import (
 	"reflect"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
 )
 
 var _ = Describe("SliceOfValues", func() {
 	It("returns a string representation of the slice", func() {
 		sliceOfValues := []reflect.Value{reflect.ValueOf("a"), reflect.ValueOf("b")}
-		Expect(prettyprint.SliceOfValues(sliceOfValues)).To(Equal("[<string> a, <string> b]"))
+		Expect(diff.SliceOfValues(sliceOfValues)).To(Equal("[<string> a, <string> b]"))
 	})
 })
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringDifference locates where two strings first differ, and for
+// multi-line strings lists the lines that differ. It returns an empty
+// string for short single-line strings, where the values say enough.
+func (style Style) stringDifference(expected string, actual string) string {
+	multiline := strings.Contains(expected, "\n") || strings.Contains(actual, "\n")
+	if expected == actual || (!multiline && len(expected) < longString && len(actual) < longString) {
+		return ""
+	}
+
+	offset := commonPrefix(expected, actual)
+	for offset > 0 && !runeStart(expected, offset) {
+		offset--
+	}
+
+	prefix := expected[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	column := utf8.RuneCountInString(prefix[strings.LastIndex(prefix, "\n")+1:]) + 1
+
+	text := fmt.Sprintf("\n    first difference at offset %d (line %d, col %d)", offset, line, column)
+	if multiline {
+		text += ":\n" + style.changedLines(expected, actual)
+	}
+
+	return text
+}
+
+// changedLines lists the lines only expected has with a "-" and the lines
+// only actual has with a "+", with "..." standing in for the lines they
+// share.
+func (style Style) changedLines(expected string, actual string) string {
+	var lines []string
+	shared, changed := false, 0
+	aligned := AlignLines(strings.Split(expected, "\n"), strings.Split(actual, "\n"))
+	for i, line := range aligned {
+		if line.Kind == ' ' {
+			shared = true
+			continue
+		}
+
+		if style.Limits.MaxElements > 0 && changed == style.Limits.MaxElements {
+			lines = append(lines, fmt.Sprintf("... %d more", countChanged(aligned[i:])))
+			break
+		}
+		changed++
+
+		text := style.Limits.truncate(line.Text)
+		if line.Kind == '-' {
+			text = style.expected("-" + text)
+		} else {
+			text = style.actual("+" + text)
+		}
+
+		if shared && len(lines) > 0 {
+			lines = append(lines, "...")
+		}
+		shared = false
+
+		lines = append(lines, text)
+	}
+
+	return indent(strings.Join(lines, "\n"), "        ")
+}
+
+func countChanged(lines []Line) int {
+	count := 0
+	for _, line := range lines {
+		if line.Kind != ' ' {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,34 @@
+package diff
+
+// FieldName is the path segment of a struct field. Paths print it as
+// .Name rather than in brackets.
+type FieldName string
+
+type StructNested struct {
+	Field            FieldName
+	NestedDifference Difference
+}
+
+func (d StructNested) Path() []interface{} {
+	return prepend(d.Field, d.NestedDifference)
+}
+
+func (d StructNested) Kind() string {
+	return d.NestedDifference.Kind()
+}
+
+func (d StructNested) Description() string {
+	return d.NestedDifference.Description()
+}
+
+func (d StructNested) Expected() interface{} {
+	return d.NestedDifference.Expected()
+}
+
+func (d StructNested) Actual() interface{} {
+	return d.NestedDifference.Actual()
+}
+
+func (d StructNested) nestedDifference() Difference {
+	return d.NestedDifference
+}
@@ -0,0 +1,107 @@
+package diff
+
+import "unicode/utf8"
+
+// Style decorates the parts of a description, for instance with terminal
+// colors. A nil function leaves its text alone.
+type Style struct {
+	// Expected and Actual decorate the values from each side.
+	Expected func(string) string
+	Actual   func(string) string
+
+	// Highlight decorates the region in which two long strings differ,
+	// within text that Expected or Actual already decorates.
+	Highlight func(string) string
+
+	// Path decorates the path in the header of each difference.
+	Path func(string) string
+
+	// Limits bound how much of each value is printed.
+	Limits Limits
+}
+
+// Plain is the Style that leaves descriptions undecorated and prints values
+// in full.
+var Plain = Style{}
+
+// Styled is implemented by differences whose descriptions can be decorated.
+// StyledDescription(Plain) returns the same text as Description.
+type Styled interface {
+	StyledDescription(style Style) string
+}
+
+func (style Style) expected(text string) string {
+	if style.Expected == nil {
+		return text
+	}
+
+	return style.Expected(text)
+}
+
+func (style Style) actual(text string) string {
+	if style.Actual == nil {
+		return text
+	}
+
+	return style.Actual(text)
+}
+
+// longString is the length from which differing strings have the region
+// in which they differ highlighted.
+const longString = 32
+
+// highlightStrings returns expected and actual with the region between
+// their common prefix and common suffix highlighted, when the style
+// highlights and either string is long. Strings cut short by the limits
+// are not highlighted.
+func (style Style) highlightStrings(expected string, actual string) (string, string) {
+	if style.Limits.truncates(expected) || style.Limits.truncates(actual) {
+		return style.Limits.truncate(expected), style.Limits.truncate(actual)
+	}
+	if style.Highlight == nil || (len(expected) < longString && len(actual) < longString) {
+		return expected, actual
+	}
+
+	prefix := commonPrefix(expected, actual)
+	suffix := commonSuffix(expected[prefix:], actual[prefix:])
+	for prefix > 0 && !runeStart(expected, prefix) {
+		prefix--
+	}
+	for suffix > 0 && !runeStart(expected, len(expected)-suffix) {
+		suffix--
+	}
+
+	highlight := func(text string) string {
+		middle := text[prefix : len(text)-suffix]
+		if middle == "" {
+			return text
+		}
+
+		return text[:prefix] + style.Highlight(middle) + text[len(text)-suffix:]
+	}
+
+	return highlight(expected), highlight(actual)
+}
+
+func commonPrefix(a string, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+
+	return n
+}
+
+func commonSuffix(a string, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+
+	return n
+}
+
+// runeStart reports whether a rune of text starts at index i.
+func runeStart(text string, i int) bool {
+	return i >= len(text) || utf8.RuneStart(text[i])
+}
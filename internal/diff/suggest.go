@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Suggestion pairs a missing map key with an extra key at the same path
+// that is likely a misspelling of it.
+type Suggestion struct {
+	MissingKey interface{}
+	ExtraKey   interface{}
+}
+
+func (s Suggestion) String() string {
+	return fmt.Sprintf("missing key '%+v'; did you mean the extra key '%+v'?", s.MissingKey, s.ExtraKey)
+}
+
+// Suggest pairs up the missing and extra map keys among differences that
+// share a path, closest keys first, and pairs each key at most once. It
+// returns the suggestions by the index of the missing key's difference.
+func Suggest(differences []Difference) map[int]Suggestion {
+	type key struct {
+		index int
+		path  string
+		text  string
+		value interface{}
+	}
+
+	var missing, extra []key
+	for i, difference := range differences {
+		path, leaf := Unwrap(difference)
+		switch leaf := leaf.(type) {
+		case MapMissingKey:
+			missing = append(missing, key{i, FormatPath(path), fmt.Sprintf("%+v", leaf.MissingKey), leaf.MissingKey})
+		case MapExtraKey:
+			extra = append(extra, key{i, FormatPath(path), fmt.Sprintf("%+v", leaf.ExtraKey), leaf.ExtraKey})
+		}
+	}
+
+	type pair struct {
+		missing, extra key
+		distance       int
+	}
+
+	var pairs []pair
+	for _, m := range missing {
+		for _, e := range extra {
+			if m.path != e.path {
+				continue
+			}
+
+			if distance := editDistance(m.text, e.text); likelyTypo(m.text, e.text, distance) {
+				pairs = append(pairs, pair{m, e, distance})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].distance < pairs[j].distance
+	})
+
+	suggestions := map[int]Suggestion{}
+	paired := map[int]bool{}
+	for _, p := range pairs {
+		if _, ok := suggestions[p.missing.index]; ok || paired[p.extra.index] {
+			continue
+		}
+
+		suggestions[p.missing.index] = Suggestion{MissingKey: p.missing.value, ExtraKey: p.extra.value}
+		paired[p.extra.index] = true
+	}
+
+	return suggestions
+}
+
+// likelyTypo reports whether two keys that are distance edits apart are
+// close enough to be the same key misspelled: a single edit, or at most a
+// third of the longer key, and fewer edits than the shorter key has
+// characters.
+func likelyTypo(a string, b string, distance int) bool {
+	shorter, longer := len([]rune(a)), len([]rune(b))
+	if shorter > longer {
+		shorter, longer = longer, shorter
+	}
+
+	return distance < shorter && (distance == 1 || distance*3 <= longer)
+}
@@ -0,0 +1,65 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+var _ = Describe("Suggest", func() {
+	missing := func(key string, path ...interface{}) diff.Difference {
+		var d diff.Difference = diff.MapMissingKey{MissingKey: key}
+		for i := len(path) - 1; i >= 0; i-- {
+			d = diff.MapNested{Key: path[i], NestedDifference: d}
+		}
+		return d
+	}
+
+	extra := func(key string, path ...interface{}) diff.Difference {
+		var d diff.Difference = diff.MapExtraKey{ExtraKey: key}
+		for i := len(path) - 1; i >= 0; i-- {
+			d = diff.MapNested{Key: path[i], NestedDifference: d}
+		}
+		return d
+	}
+
+	It("pairs a missing key with a similar extra key", func() {
+		suggestions := diff.Suggest([]diff.Difference{
+			extra("instance_group"),
+			missing("instance_groups"),
+		})
+
+		Expect(suggestions).To(Equal(map[int]diff.Suggestion{
+			1: {MissingKey: "instance_groups", ExtraKey: "instance_group"},
+		}))
+		Expect(suggestions[1].String()).To(Equal("missing key 'instance_groups'; did you mean the extra key 'instance_group'?"))
+	})
+
+	It("pairs each key at most once, closest keys first", func() {
+		suggestions := diff.Suggest([]diff.Difference{
+			missing("network"),
+			missing("networks"),
+			extra("networks_"),
+		})
+
+		Expect(suggestions).To(Equal(map[int]diff.Suggestion{
+			1: {MissingKey: "networks", ExtraKey: "networks_"},
+		}))
+	})
+
+	It("only pairs keys at the same path", func() {
+		Expect(diff.Suggest([]diff.Difference{
+			missing("name", "jobs"),
+			extra("nmae", "properties"),
+		})).To(BeEmpty())
+	})
+
+	It("does not pair keys that are too different", func() {
+		Expect(diff.Suggest([]diff.Difference{
+			missing("port"),
+			extra("host"),
+			missing("a"),
+			extra("b"),
+		})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,31 @@
+package prettyprint
+
+import (
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+const (
+	bold         = "\x1b[1m"
+	red          = "\x1b[31m"
+	green        = "\x1b[32m"
+	reverse      = "\x1b[7m"
+	reverseOff   = "\x1b[27m"
+	defaultColor = "\x1b[0m"
+)
+
+// Colors shows paths in bold, expected values in green and actual values
+// in red, with the region in which long strings differ in reverse video.
+var Colors = diff.Style{
+	Expected: func(text string) string {
+		return green + text + defaultColor
+	},
+	Actual: func(text string) string {
+		return red + text + defaultColor
+	},
+	Highlight: func(text string) string {
+		return reverse + text + reverseOff
+	},
+	Path: func(text string) string {
+		return bold + text + defaultColor
+	},
+}
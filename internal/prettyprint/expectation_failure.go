@@ -1,108 +1,74 @@
 package prettyprint
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
 )
 
+// ExpectationFailure formats a difference under an "error at [path]:"
+// header, followed by its description.
 func ExpectationFailure(difference diff.Difference) string {
-	return "error at " + anyFailure(difference)
+	return block("error at ", difference)
 }
 
-func anyFailure(difference diff.Difference) string {
-	switch difference := difference.(type) {
-	case diff.NoDifference:
-		return ""
-	case diff.MapNested:
-		return mapNestedFailure(difference)
-
-	case diff.MapMissingKey:
-		return mapMissingKeyFailure(difference)
-
-	case diff.MapExtraKey:
-		return mapExtraKeyFailure(difference)
-
-	case diff.SliceNested:
-		return sliceNestedFailure(difference)
-
-	case diff.SliceExtraElements:
-		return sliceExtraElementsFailure(difference)
-
-	case diff.SliceMissingElements:
-		return sliceMissingElementsFailure(difference)
-
-	case diff.PrimitiveTypeMismatch:
-		return primitiveTypeMismatchFailure(difference)
-
-	case diff.PrimitiveValueMismatch:
-		return primitiveValueMismatchFailure(difference)
-
-	default:
-		panic("unexpected difference type")
-	}
+// ExpectationFailures formats each difference as ExpectationFailure does,
+// in style, and adds any text that annotate returns for it. A missing map
+// key that looks like a misspelling of an extra key is followed by a "did
+// you mean" line.
+func ExpectationFailures(differences []diff.Difference, annotate func(diff.Difference) string, style diff.Style) string {
+	return blocks("error at ", differences, annotate, style)
 }
 
-func mapNestedFailure(difference diff.MapNested) string {
-	return fmt.Sprintf("[%+v]%s", difference.Key, anyFailure(difference.NestedDifference))
+// Warnings formats differences that do not fail the match, such as loose
+// matches, under "warning at [path]:" headers.
+func Warnings(warnings []diff.Difference, annotate func(diff.Difference) string, style diff.Style) string {
+	return blocks("warning at ", warnings, annotate, style)
 }
 
-func mapMissingKeyFailure(difference diff.MapMissingKey) string {
-	return fmt.Sprintf(`:
-  missing key:
-    Expected
-        %s
-    to contain key
-        <%T> %+v`, SliceOfValues(difference.AllKeys), difference.MissingKey, difference.MissingKey)
-}
+func blocks(header string, differences []diff.Difference, annotate func(diff.Difference) string, style diff.Style) string {
+	suggestions := diff.Suggest(differences)
+
+	var failures []string
+	for i, difference := range differences {
+		failure := styledBlock(header, difference, style)
+		if suggestion, ok := suggestions[i]; ok {
+			failure += "\n  " + suggestion.String()
+		}
+		if annotate != nil {
+			if annotation := annotate(difference); annotation != "" {
+				failure += "\n" + annotation
+			}
+		}
+
+		failures = append(failures, failure)
+	}
 
-func mapExtraKeyFailure(difference diff.MapExtraKey) string {
-	return fmt.Sprintf(`:
-  extra key found:
-    Expected
-        %s
-    not to contain key
-        <%T> %+v`, SliceOfValues(difference.AllKeys), difference.ExtraKey, difference.ExtraKey)
+	return strings.Join(failures, "\n\n")
 }
 
-func sliceNestedFailure(difference diff.SliceNested) string {
-	return fmt.Sprintf("[%d]%s", difference.Index, anyFailure(difference.NestedDifference))
+func block(header string, difference diff.Difference) string {
+	return styledBlock(header, difference, diff.Plain)
 }
 
-func sliceExtraElementsFailure(difference diff.SliceExtraElements) string {
-	return fmt.Sprintf(`:
-  extra elements found:
-    Expected
-        %s
-    not to contain elements
-        %s`, SliceAsValue(difference.AllElements), SliceAsValue(difference.ExtraElements))
-}
+// styledBlock formats a difference like block does, with its path and, when
+// it implements diff.Styled, its description in style.
+func styledBlock(header string, difference diff.Difference, style diff.Style) string {
+	path := diff.FormatPath(difference.Path())
+	if style.Path != nil {
+		path = style.Path(path)
+	}
 
-func sliceMissingElementsFailure(difference diff.SliceMissingElements) string {
-	return fmt.Sprintf(`:
-  missing elements:
-    Expected
-        %s
-    to contain elements
-        %s`, SliceAsValue(difference.AllElements), SliceAsValue(difference.MissingElements))
-}
+	description := difference.Description()
+	if _, leaf := diff.Unwrap(difference); leaf != nil {
+		if styled, ok := leaf.(diff.Styled); ok {
+			description = styled.StyledDescription(style)
+		}
+	}
 
-func primitiveTypeMismatchFailure(difference diff.PrimitiveTypeMismatch) string {
-	return fmt.Sprintf(`:
-  type mismatch:
-    Expected
-        <%T> %+v
-    to be of type
-        <%s>`, difference.ActualValue, difference.ActualValue, difference.ExpectedType)
-}
+	if description == "" {
+		return header + path
+	}
 
-func primitiveValueMismatchFailure(difference diff.PrimitiveValueMismatch) string {
-	return fmt.Sprintf(`:
-  value mismatch:
-    Expected
-        <%T> %+v
-    to equal
-        <%T> %+v`,
-		difference.ActualValue, difference.ActualValue,
-		difference.ExpectedValue, difference.ExpectedValue)
+	return header + path + ":\n" + description
 }
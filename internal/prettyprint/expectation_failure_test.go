@@ -1,6 +1,7 @@
 package prettyprint_test
 
 import (
+	"errors"
 	"reflect"
 
 	. "github.com/onsi/ginkgo"
@@ -9,6 +10,31 @@ import (
 	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
 )
 
+type quotaExceeded struct {
+	limit int
+	used  int
+}
+
+func (quotaExceeded) Path() []interface{} {
+	return []interface{}{"quota"}
+}
+
+func (quotaExceeded) Kind() string {
+	return "quota exceeded"
+}
+
+func (d quotaExceeded) Description() string {
+	return "  quota exceeded:\n    used more than the limit"
+}
+
+func (d quotaExceeded) Expected() interface{} {
+	return d.limit
+}
+
+func (d quotaExceeded) Actual() interface{} {
+	return d.used
+}
+
 var _ = Describe("ExpectationFailure", func() {
 	It("formats complex diffs correctly", func() {
 		failure := prettyprint.ExpectationFailure(diff.MapNested{
@@ -35,6 +61,17 @@ var _ = Describe("ExpectationFailure", func() {
 		Expect(failure).To(Equal("error at "))
 	})
 
+	It("formats differences defined outside the diff package", func() {
+		failure := prettyprint.ExpectationFailure(diff.MapNested{
+			Key:              "limits",
+			NestedDifference: quotaExceeded{limit: 2, used: 3},
+		})
+
+		Expect(failure).To(Equal(`error at [limits][quota]:
+  quota exceeded:
+    used more than the limit`))
+	})
+
 	Context("when printing primitives", func() {
 		It("formats type mismatches correctly", func() {
 			failure := prettyprint.ExpectationFailure(diff.PrimitiveTypeMismatch{
@@ -62,6 +99,40 @@ var _ = Describe("ExpectationFailure", func() {
 			Expect(failure).To(ContainSubstring("        <string> red"))
 			Expect(failure).To(ContainSubstring("    to equal"))
 			Expect(failure).To(ContainSubstring("        <string> blue"))
+			Expect(failure).NotTo(ContainSubstring("first difference"))
+		})
+
+		It("locates where long strings first differ", func() {
+			failure := prettyprint.ExpectationFailure(diff.PrimitiveValueMismatch{
+				ExpectedValue: "https://example.com/releases/v1.2.3/download.tgz",
+				ActualValue:   "https://example.com/releases/v1.4.3/download.tgz",
+			})
+
+			Expect(failure).To(HaveSuffix("\n        <string> https://example.com/releases/v1.2.3/download.tgz\n    first difference at offset 32 (line 1, col 33)"))
+		})
+
+		It("lists the lines in which multi-line strings differ", func() {
+			failure := prettyprint.ExpectationFailure(diff.PrimitiveValueMismatch{
+				ExpectedValue: "#!/bin/bash\nset -e\necho starting\nexec server --port 8080\n",
+				ActualValue:   "#!/bin/bash\nset -eu\necho starting\nexec server --port 8081\n",
+			})
+
+			Expect(failure).To(HaveSuffix(`
+    first difference at offset 18 (line 2, col 7):
+        -set -e
+        +set -eu
+        ...
+        -exec server --port 8080
+        +exec server --port 8081`))
+		})
+
+		It("counts columns in runes", func() {
+			failure := prettyprint.ExpectationFailure(diff.PrimitiveValueMismatch{
+				ExpectedValue: "name: Zoë\nrole: admin",
+				ActualValue:   "name: Zoë\nrole: owner",
+			})
+
+			Expect(failure).To(ContainSubstring("first difference at offset 17 (line 2, col 7):\n        -role: admin\n        +role: owner"))
 		})
 	})
 
@@ -164,4 +235,309 @@ var _ = Describe("ExpectationFailure", func() {
 			Expect(failure).To(ContainSubstring("        [<int> 3, <int> 4]"))
 		})
 	})
+
+	Context("when printing slices keyed by a field", func() {
+		It("formats nested differences with the element key", func() {
+			failure := prettyprint.ExpectationFailure(diff.MapNested{
+				Key: "instance_groups",
+				NestedDifference: diff.SliceKeyedNested{
+					Key: diff.ElementKey{Field: "name", Value: "router"},
+					NestedDifference: diff.MapNested{
+						Key: "instances",
+						NestedDifference: diff.PrimitiveValueMismatch{
+							ExpectedValue: 2,
+							ActualValue:   3,
+						},
+					},
+				},
+			})
+
+			Expect(failure).To(ContainSubstring("error at [instance_groups][name=router][instances]:"))
+		})
+
+		It("formats extra element differences correctly", func() {
+			failure := prettyprint.ExpectationFailure(diff.SliceExtraKeyedElement{
+				ExtraKey: diff.ElementKey{Field: "name", Value: "uaa"},
+				AllKeys: []diff.ElementKey{
+					{Field: "name", Value: "router"},
+					{Field: "name", Value: "uaa"},
+				},
+			})
+
+			Expect(failure).To(ContainSubstring("error at :"))
+			Expect(failure).To(ContainSubstring("  extra element found:"))
+			Expect(failure).To(ContainSubstring("    Expected"))
+			Expect(failure).To(ContainSubstring("        [<string> router, <string> uaa]"))
+			Expect(failure).To(ContainSubstring("    not to contain element with name"))
+			Expect(failure).To(ContainSubstring("        <string> uaa"))
+		})
+
+		It("formats missing element differences correctly", func() {
+			failure := prettyprint.ExpectationFailure(diff.SliceMissingKeyedElement{
+				MissingKey: diff.ElementKey{Field: "name", Value: "api"},
+				AllKeys:    []diff.ElementKey{{Field: "name", Value: "router"}},
+			})
+
+			Expect(failure).To(ContainSubstring("error at :"))
+			Expect(failure).To(ContainSubstring("  missing element:"))
+			Expect(failure).To(ContainSubstring("    Expected"))
+			Expect(failure).To(ContainSubstring("        [<string> router]"))
+			Expect(failure).To(ContainSubstring("    to contain element with name"))
+			Expect(failure).To(ContainSubstring("        <string> api"))
+		})
+	})
+
+	Context("when printing slices compared without order", func() {
+		It("formats unmatched elements on each side", func() {
+			failure := prettyprint.ExpectationFailure(diff.SliceUnorderedMismatch{
+				MissingElements: reflect.ValueOf([]int{3}),
+				ExtraElements:   reflect.ValueOf([]int{5, 6}),
+			})
+
+			Expect(failure).To(ContainSubstring("error at :"))
+			Expect(failure).To(ContainSubstring("  unmatched elements (ignoring order):"))
+			Expect(failure).To(ContainSubstring("    Expected elements not found in actual"))
+			Expect(failure).To(ContainSubstring("        [<int> 3]"))
+			Expect(failure).To(ContainSubstring("    Actual elements not found in expected"))
+			Expect(failure).To(ContainSubstring("        [<int> 5, <int> 6]"))
+		})
+
+		It("omits a side with no unmatched elements", func() {
+			failure := prettyprint.ExpectationFailure(diff.SliceUnorderedMismatch{
+				MissingElements: reflect.ValueOf([]int{}),
+				ExtraElements:   reflect.ValueOf([]int{5}),
+			})
+
+			Expect(failure).NotTo(ContainSubstring("Expected elements not found in actual"))
+			Expect(failure).To(ContainSubstring("    Actual elements not found in expected"))
+		})
+	})
+
+	Context("when printing matcher failures", func() {
+		It("indents the failure message of the matcher", func() {
+			failure := prettyprint.ExpectationFailure(diff.MapNested{
+				Key: "password",
+				NestedDifference: diff.MatcherFailure{
+					FailureMessage: "Expected\n    <string>: abc\nto have length 32",
+				},
+			})
+
+			Expect(failure).To(ContainSubstring("error at [password]:"))
+			Expect(failure).To(ContainSubstring("  matcher failed:"))
+			Expect(failure).To(ContainSubstring("    Expected\n        <string>: abc\n    to have length 32"))
+		})
+
+		It("formats matcher errors", func() {
+			failure := prettyprint.ExpectationFailure(diff.MatcherFailure{
+				Error: errors.New("cannot match"),
+			})
+
+			Expect(failure).To(ContainSubstring("error at :"))
+			Expect(failure).To(ContainSubstring("  matcher error:"))
+			Expect(failure).To(ContainSubstring("    cannot match"))
+		})
+	})
+
+	Context("when printing traversal limits", func() {
+		It("formats cycles with the paths each side refers back to", func() {
+			failure := prettyprint.ExpectationFailure(diff.StructNested{
+				Field: "Next",
+				NestedDifference: diff.CycleDetected{
+					ExpectedCycles: true,
+					ExpectedTarget: nil,
+				},
+			})
+
+			Expect(failure).To(Equal(`error at .Next:
+  cycle detected:
+    expected refers back to the root
+    actual does not refer back to an enclosing value`))
+		})
+
+		It("formats exceeded depths without printing the values", func() {
+			failure := prettyprint.ExpectationFailure(diff.MapNested{
+				Key: "a",
+				NestedDifference: diff.DepthExceeded{
+					MaxDepth:      1,
+					ExpectedValue: map[string]int{"b": 1},
+					ActualValue:   map[string]int{"b": 2},
+				},
+			})
+
+			Expect(failure).To(Equal(`error at [a]:
+  depth exceeded:
+    values of type
+        <map[string]int>
+    differ below the maximum depth of 1`))
+		})
+	})
+
+	Context("when printing documents of a stream", func() {
+		It("formats nested differences with the document", func() {
+			failure := prettyprint.ExpectationFailure(diff.DocumentNested{
+				Document: diff.DocumentKey{Name: "1", ExpectedIndex: 1, ActualIndex: 1},
+				NestedDifference: diff.MapNested{
+					Key: "port",
+					NestedDifference: diff.PrimitiveValueMismatch{
+						ExpectedValue: 80,
+						ActualValue:   8080,
+					},
+				},
+			})
+
+			Expect(failure).To(ContainSubstring("error at [document 1][port]:"))
+		})
+
+		It("formats extra documents correctly", func() {
+			failure := prettyprint.ExpectationFailure(diff.DocumentExtra{
+				ExtraDocument: diff.DocumentKey{Name: "2"},
+				AllDocuments:  []diff.DocumentKey{{Name: "0"}, {Name: "1"}, {Name: "2"}},
+			})
+
+			Expect(failure).To(ContainSubstring("error at :"))
+			Expect(failure).To(ContainSubstring("  extra document found:"))
+			Expect(failure).To(ContainSubstring("        [document 0, document 1, document 2]"))
+			Expect(failure).To(ContainSubstring("    not to contain\n        document 2"))
+		})
+
+		It("formats missing documents correctly", func() {
+			failure := prettyprint.ExpectationFailure(diff.DocumentMissing{
+				MissingDocument: diff.DocumentKey{Name: "1"},
+				AllDocuments:    []diff.DocumentKey{{Name: "0"}},
+			})
+
+			Expect(failure).To(ContainSubstring("error at :"))
+			Expect(failure).To(ContainSubstring("  missing document:"))
+			Expect(failure).To(ContainSubstring("        [document 0]"))
+			Expect(failure).To(ContainSubstring("    to contain\n        document 1"))
+		})
+	})
+
+	Describe("Warnings", func() {
+		It("formats each warning under its path", func() {
+			warnings := prettyprint.Warnings([]diff.Difference{
+				diff.LooseMatch{
+					Location:      []interface{}{"version"},
+					ExpectedValue: 1.0,
+					ActualValue:   "1.0",
+					Form:          "1",
+				},
+			}, func(diff.Difference) string { return "  location: actual line 1, col 10" }, diff.Plain)
+
+			Expect(warnings).To(Equal(`warning at [version]:
+  loose match:
+    Expected
+        <string> 1.0
+    matched
+        <float64> 1
+    only by its string form "1"
+  location: actual line 1, col 10`))
+		})
+	})
+
+	Describe("ExpectationFailures", func() {
+		It("formats each difference with its own path", func() {
+			failure := prettyprint.ExpectationFailures([]diff.Difference{
+				diff.MapNested{
+					Key: "age",
+					NestedDifference: diff.PrimitiveValueMismatch{
+						ExpectedValue: 11,
+						ActualValue:   12,
+					},
+				},
+				diff.MapNested{
+					Key: "name",
+					NestedDifference: diff.PrimitiveValueMismatch{
+						ExpectedValue: "bob",
+						ActualValue:   "alice",
+					},
+				},
+			}, nil, diff.Plain)
+
+			Expect(failure).To(ContainSubstring("error at [age]:"))
+			Expect(failure).To(ContainSubstring("        <int> 12"))
+			Expect(failure).To(ContainSubstring("error at [name]:"))
+			Expect(failure).To(ContainSubstring("        <string> alice"))
+		})
+
+		It("returns an empty string when there are no differences", func() {
+			Expect(prettyprint.ExpectationFailures(nil, nil, diff.Plain)).To(BeEmpty())
+		})
+
+		It("adds annotations after each difference", func() {
+			failure := prettyprint.ExpectationFailures([]diff.Difference{
+				diff.PrimitiveValueMismatch{ExpectedValue: 1, ActualValue: 2},
+				diff.PrimitiveValueMismatch{ExpectedValue: 3, ActualValue: 4},
+			}, func(difference diff.Difference) string {
+				if difference.(diff.PrimitiveValueMismatch).ExpectedValue == 1 {
+					return "  note: first"
+				}
+				return ""
+			}, diff.Plain)
+
+			Expect(failure).To(ContainSubstring("        <int> 1\n  note: first\n\nerror at :"))
+			Expect(failure).To(HaveSuffix("        <int> 3"))
+		})
+
+		It("suggests an extra key that a missing key may be misspelled as", func() {
+			failure := prettyprint.ExpectationFailures([]diff.Difference{
+				diff.MapExtraKey{ExtraKey: "instance_group"},
+				diff.MapMissingKey{MissingKey: "instance_groups"},
+			}, func(diff.Difference) string { return "  location: line 1" }, diff.Plain)
+
+			Expect(failure).To(HaveSuffix("        <string> instance_groups\n  missing key 'instance_groups'; did you mean the extra key 'instance_group'?\n  location: line 1"))
+		})
+
+		Context("with color", func() {
+			It("makes the path bold, actual values red and expected values green", func() {
+				failure := prettyprint.ExpectationFailures([]diff.Difference{
+					diff.MapNested{
+						Key: "age",
+						NestedDifference: diff.PrimitiveValueMismatch{
+							ExpectedValue: 11,
+							ActualValue:   12,
+						},
+					},
+				}, nil, prettyprint.Colors)
+
+				Expect(failure).To(Equal("error at \x1b[1m[age]\x1b[0m:\n  value mismatch:\n    Expected\n        \x1b[31m<int> 12\x1b[0m\n    to equal\n        \x1b[32m<int> 11\x1b[0m"))
+			})
+
+			It("highlights the region in which long strings differ", func() {
+				failure := prettyprint.ExpectationFailures([]diff.Difference{
+					diff.PrimitiveValueMismatch{
+						ExpectedValue: "https://example.com/releases/v1.2.3/download.tgz",
+						ActualValue:   "https://example.com/releases/v1.4.3/download.tgz",
+					},
+				}, nil, prettyprint.Colors)
+
+				Expect(failure).To(ContainSubstring("\x1b[31m<string> https://example.com/releases/v1.\x1b[7m4\x1b[27m.3/download.tgz\x1b[0m"))
+				Expect(failure).To(ContainSubstring("\x1b[32m<string> https://example.com/releases/v1.\x1b[7m2\x1b[27m.3/download.tgz\x1b[0m"))
+			})
+
+			It("colors the lines in which multi-line strings differ", func() {
+				failure := prettyprint.ExpectationFailures([]diff.Difference{
+					diff.PrimitiveValueMismatch{ExpectedValue: "a\nb", ActualValue: "a\nc"},
+				}, nil, prettyprint.Colors)
+
+				Expect(failure).To(HaveSuffix("\n        \x1b[32m-b\x1b[0m\n        \x1b[31m+c\x1b[0m"))
+			})
+
+			It("does not highlight short strings", func() {
+				failure := prettyprint.ExpectationFailures([]diff.Difference{
+					diff.PrimitiveValueMismatch{ExpectedValue: "bob", ActualValue: "bab"},
+				}, nil, prettyprint.Colors)
+
+				Expect(failure).NotTo(ContainSubstring("\x1b[7m"))
+			})
+
+			It("leaves the descriptions of other differences uncolored", func() {
+				failure := prettyprint.ExpectationFailures([]diff.Difference{
+					quotaExceeded{limit: 2, used: 3},
+				}, nil, prettyprint.Colors)
+
+				Expect(failure).To(Equal("error at \x1b[1m[quota]\x1b[0m:\n  quota exceeded:\n    used more than the limit"))
+			})
+		})
+	})
 })
@@ -0,0 +1,86 @@
+package prettyprint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// UnifiedDiff returns a unified diff of the lines of expected and actual,
+// with the given number of unchanged lines around each change. It returns
+// an empty string when the texts are equal.
+func UnifiedDiff(expected string, actual string, context int) string {
+	lines := diff.AlignLines(splitLines(expected), splitLines(actual))
+
+	var hunks []string
+	for start := 0; start < len(lines); {
+		if lines[start].Kind == ' ' {
+			start++
+			continue
+		}
+
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+
+		last := start
+		for i := start; i < len(lines) && i <= last+2*context; i++ {
+			if lines[i].Kind != ' ' {
+				last = i
+			}
+		}
+
+		end := last + context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		hunks = append(hunks, hunk(lines[first:end]))
+		start = end
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	return "--- expected\n+++ actual\n" + strings.Join(hunks, "\n")
+}
+
+func hunk(lines []diff.Line) string {
+	expectedStart, actualStart := lines[0].ExpectedLine, lines[0].ActualLine
+	expectedCount, actualCount := 0, 0
+
+	var body []string
+	for _, line := range lines {
+		if line.Kind != '+' {
+			expectedCount++
+		}
+		if line.Kind != '-' {
+			actualCount++
+		}
+
+		body = append(body, string(line.Kind)+line.Text)
+	}
+
+	return fmt.Sprintf("@@ -%s +%s @@\n%s", hunkRange(expectedStart, expectedCount),
+		hunkRange(actualStart, actualCount), strings.Join(body, "\n"))
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
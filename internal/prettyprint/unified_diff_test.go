@@ -0,0 +1,76 @@
+package prettyprint_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
+)
+
+var _ = Describe("UnifiedDiff", func() {
+	lines := func(lines ...string) string {
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	It("returns an empty string when the texts are equal", func() {
+		Expect(prettyprint.UnifiedDiff(lines("a", "b"), lines("a", "b"), 3)).To(BeEmpty())
+	})
+
+	It("shows changed lines with surrounding context", func() {
+		expected := lines("a", "b", "c", "d", "e", "f", "g")
+		actual := lines("a", "b", "c", "D", "e", "f", "g")
+
+		Expect(prettyprint.UnifiedDiff(expected, actual, 1)).To(Equal(strings.Join([]string{
+			"--- expected",
+			"+++ actual",
+			"@@ -3,3 +3,3 @@",
+			" c",
+			"-d",
+			"+D",
+			" e",
+		}, "\n")))
+	})
+
+	It("shows added and removed lines", func() {
+		expected := lines("a", "b", "c")
+		actual := lines("a", "c", "d")
+
+		Expect(prettyprint.UnifiedDiff(expected, actual, 3)).To(Equal(strings.Join([]string{
+			"--- expected",
+			"+++ actual",
+			"@@ -1,3 +1,3 @@",
+			" a",
+			"-b",
+			" c",
+			"+d",
+		}, "\n")))
+	})
+
+	It("splits distant changes into separate hunks", func() {
+		expected := lines("1", "2", "3", "4", "5", "6", "7", "8", "9")
+		actual := lines("one", "2", "3", "4", "5", "6", "7", "8", "nine")
+
+		Expect(prettyprint.UnifiedDiff(expected, actual, 1)).To(Equal(strings.Join([]string{
+			"--- expected",
+			"+++ actual",
+			"@@ -1,2 +1,2 @@",
+			"-1",
+			"+one",
+			" 2",
+			"@@ -8,2 +8,2 @@",
+			" 8",
+			"-9",
+			"+nine",
+		}, "\n")))
+	})
+
+	It("handles an empty side", func() {
+		Expect(prettyprint.UnifiedDiff("", lines("a"), 3)).To(Equal(strings.Join([]string{
+			"--- expected",
+			"+++ actual",
+			"@@ -0,0 +1,1 @@",
+			"+a",
+		}, "\n")))
+	})
+})
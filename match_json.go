@@ -0,0 +1,203 @@
+package gomegamatchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+)
+
+func HelpfullyMatchJSON(expected interface{}) types.GomegaMatcher {
+	return &HelpfullyMatchJSONMatcher{
+		JSONToMatch: expected,
+	}
+}
+
+type HelpfullyMatchJSONMatcher struct {
+	JSONToMatch interface{}
+
+	// MaxDifferences caps the number of differences listed in the failure
+	// message. Zero lists every difference.
+	MaxDifferences int
+
+	// NumericValues compares numbers by value, so that 8080 and 8080.0
+	// match. Floats may also differ by up to AbsoluteTolerance, or by up to
+	// RelativeTolerance times the larger of the two. Setting either
+	// tolerance implies NumericValues.
+	NumericValues     bool
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+
+	inputs documentInputs
+}
+
+func (matcher *HelpfullyMatchJSONMatcher) Match(actual interface{}) (success bool, err error) {
+	equal, _, err := matcher.equal(matcher.JSONToMatch, actual)
+	if err != nil {
+		return false, err
+	}
+
+	return equal, nil
+}
+
+func (matcher *HelpfullyMatchJSONMatcher) FailureMessage(actual interface{}) (message string) {
+	_, message, err := matcher.equal(matcher.JSONToMatch, actual)
+	if err != nil {
+		return err.Error()
+	}
+
+	return message
+}
+
+func (matcher *HelpfullyMatchJSONMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	actual, _, _ = matcher.inputs.readActual(actual)
+	expected, _, _ := matcher.inputs.readExpected(matcher.JSONToMatch)
+
+	actualString, _ := matcher.prettyPrint(actual)
+	expectedString, _ := matcher.prettyPrint(expected)
+	return format.Message(actualString, "not to match JSON of", expectedString)
+}
+
+func (matcher *HelpfullyMatchJSONMatcher) equal(expected interface{}, actual interface{}) (bool, string, error) {
+	actual, actualFile, err := matcher.inputs.readActual(actual)
+	if err != nil {
+		return false, "", err
+	}
+
+	expected, expectedFile, err := matcher.inputs.readExpected(expected)
+	if err != nil {
+		return false, "", err
+	}
+
+	actualValue, err := matcher.decode(actual)
+	if err != nil {
+		return false, "", inFile(actualFile, err)
+	}
+
+	expectedValue, err := matcher.decode(expected)
+	if err != nil {
+		return false, "", inFile(expectedFile, err)
+	}
+
+	equal, message := compareDocuments(expectedValue, actualValue, deepequal.Options{
+		MaxDifferences:    matcher.MaxDifferences,
+		NumericValues:     matcher.NumericValues,
+		AbsoluteTolerance: matcher.AbsoluteTolerance,
+		RelativeTolerance: matcher.RelativeTolerance,
+	}, nil)
+	if equal {
+		return true, "", nil
+	}
+
+	return false, fixtureNames(expectedFile, actualFile) + message, nil
+}
+
+func (matcher *HelpfullyMatchJSONMatcher) prettyPrint(input interface{}) (formatted string, err error) {
+	data, err := matcher.decode(input)
+	if err != nil {
+		return "", err
+	}
+	buf, _ := json.MarshalIndent(data, "", "  ")
+
+	return string(buf), nil
+}
+
+func (matcher *HelpfullyMatchJSONMatcher) decode(input interface{}) (interface{}, error) {
+	inputString, ok := toString(input)
+	if !ok {
+		return nil, fmt.Errorf("HelpfullyMatchJSONMatcher matcher requires a string, stringer, io.Reader or File.  Got:\n%s", format.Object(input, 1))
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(inputString))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, jsonError(inputString, err)
+	}
+
+	rest := inputString[decoder.InputOffset():]
+	if trimmed := strings.TrimLeft(rest, " \t\r\n"); trimmed != "" {
+		offset := int64(len(inputString)-len(trimmed)) + 1
+		line, column := lineAndColumn(inputString, offset)
+		return nil, fmt.Errorf("invalid JSON at line %d, column %d: unexpected data after top-level value", line, column)
+	}
+
+	return jsonNumbers(data), nil
+}
+
+// jsonNumbers replaces json.Number values with an int when the number is
+// an integer that fits, a float64 when it has a fraction or exponent, and
+// leaves integers too big for an int as json.Number.
+func jsonNumbers(data interface{}) interface{} {
+	switch data := data.(type) {
+	case map[string]interface{}:
+		for key, value := range data {
+			data[key] = jsonNumbers(value)
+		}
+		return data
+
+	case []interface{}:
+		for i, value := range data {
+			data[i] = jsonNumbers(value)
+		}
+		return data
+
+	case json.Number:
+		if strings.ContainsAny(string(data), ".eE") {
+			if float, err := data.Float64(); err == nil {
+				return float
+			}
+			return data
+		}
+
+		if integer, err := strconv.ParseInt(string(data), 10, strconv.IntSize); err == nil {
+			return int(integer)
+		}
+		return data
+
+	default:
+		return data
+	}
+}
+
+func jsonError(input string, err error) error {
+	var offset int64
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	default:
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			offset = int64(len(input))
+			break
+		}
+		return err
+	}
+
+	line, column := lineAndColumn(input, offset)
+	return fmt.Errorf("invalid JSON at line %d, column %d: %s", line, column, err)
+}
+
+// lineAndColumn locates the last of the first offset bytes of input, which
+// is where encoding/json reports an error.
+func lineAndColumn(input string, offset int64) (int, int) {
+	if offset > int64(len(input)) {
+		offset = int64(len(input))
+	}
+	if offset > 0 {
+		offset--
+	}
+
+	consumed := input[:offset]
+	line := strings.Count(consumed, "\n") + 1
+	column := len(consumed) - strings.LastIndex(consumed, "\n")
+
+	return line, column
+}
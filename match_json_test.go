@@ -0,0 +1,200 @@
+package gomegamatchers_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+var _ = Describe("HelpfullyMatchJSONMatcher", func() {
+	var animals, plants string
+
+	BeforeEach(func() {
+		animals = `[{"cats": ["lion"]}, {"fish": ["salmon"]}]`
+		plants = `[{"tropical": ["palm"]}, {"desert": ["cactus"]}]`
+	})
+
+	Describe("Match", func() {
+		Context("when arguments are strings", func() {
+			It("returns true when the JSON matches", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(animals).Match(animals)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("returns true when the JSON only differs in whitespace and key order", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(`{"a": 1, "b": 2}`).Match("{\n  \"b\": 2,\n  \"a\": 1\n}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("returns false when the JSON does not match", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(animals).Match(plants)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+			})
+		})
+
+		Context("when an input is a byte slice", func() {
+			It("returns true when the JSON matches", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON([]byte(animals)).Match(animals)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+		})
+
+		Context("when an input is a Stringer", func() {
+			It("returns true when the JSON matches", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(animalStringer{Data: animals}).Match(animals)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+		})
+
+		Context("when an input is an io.Reader", func() {
+			It("returns true when the JSON matches", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(strings.NewReader(animals)).Match(bytes.NewBufferString(animals))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("reads the reader only once across Match and FailureMessage", func() {
+				matcher := gomegamatchers.HelpfullyMatchJSON(animals)
+				actual := strings.NewReader(plants)
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				Expect(matcher.FailureMessage(actual)).To(ContainSubstring("error at [0]:"))
+			})
+		})
+
+		Context("when an input is a File", func() {
+			var path string
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "animals-*.json")
+				Expect(err).NotTo(HaveOccurred())
+				defer file.Close()
+
+				_, err = file.WriteString(animals)
+				Expect(err).NotTo(HaveOccurred())
+				path = file.Name()
+			})
+
+			AfterEach(func() {
+				os.Remove(path)
+			})
+
+			It("compares the contents of the file", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(gomegamatchers.FromFile(path)).Match(animals)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("names the file in failure messages", func() {
+				message := gomegamatchers.HelpfullyMatchJSON(gomegamatchers.FromFile(path)).FailureMessage(plants)
+				Expect(message).To(HavePrefix("comparing expected " + path + "\n\nerror at [0]"))
+
+				message = gomegamatchers.HelpfullyMatchJSON(animals).FailureMessage(gomegamatchers.FromFile(path))
+				Expect(message).To(BeEmpty())
+			})
+
+			It("names the file when its JSON is invalid", func() {
+				_, err := gomegamatchers.HelpfullyMatchJSON(animals).Match(gomegamatchers.FromFile("fixtures/santa_monica_correct.yml"))
+				Expect(err).To(MatchError(HavePrefix("fixtures/santa_monica_correct.yml: invalid JSON at line 1, column")))
+			})
+		})
+
+		Context("when comparing numbers", func() {
+			It("distinguishes integers from floats", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(`{"port": 8080}`).Match(`{"port": 8080.0}`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+			})
+
+			It("compares integers that overflow an int exactly", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchJSON(`{"id": 123456789012345678901234567890}`).Match(`{"id": 123456789012345678901234567890}`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				isMatch, err = gomegamatchers.HelpfullyMatchJSON(`{"id": 123456789012345678901234567890}`).Match(`{"id": 123456789012345678901234567891}`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+			})
+
+			It("compares integers and floats by value when asked to", func() {
+				matcher := &gomegamatchers.HelpfullyMatchJSONMatcher{
+					JSONToMatch:   `{"port": 8080, "id": 123456789012345678901234567890}`,
+					NumericValues: true,
+				}
+
+				isMatch, err := matcher.Match(`{"port": 8080.0, "id": 123456789012345678901234567890}`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				isMatch, err = matcher.Match(`{"port": 8080.5, "id": 123456789012345678901234567891}`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+				Expect(matcher.FailureMessage(`{"port": 8080.5, "id": 123456789012345678901234567890}`)).To(ContainSubstring("error at [port]:\n  value mismatch:"))
+			})
+		})
+
+		Describe("errors", func() {
+			It("returns an error when one of the inputs is not a string, byte slice, Stringer or io.Reader", func() {
+				_, err := gomegamatchers.HelpfullyMatchJSON(animals).Match(123213)
+				Expect(err.Error()).To(ContainSubstring("HelpfullyMatchJSONMatcher matcher requires a string, stringer, io.Reader or File."))
+				Expect(err.Error()).To(ContainSubstring("Got:\n    <int>: 123213"))
+			})
+
+			It("returns an error with the line and column when the JSON is invalid", func() {
+				_, err := gomegamatchers.HelpfullyMatchJSON(animals).Match("{\n  \"a\": 1,\n  \"b\" 2\n}")
+				Expect(err.Error()).To(ContainSubstring("invalid JSON at line 3, column 7:"))
+				Expect(err.Error()).To(ContainSubstring("invalid character '2' after object key"))
+			})
+
+			It("returns an error when the JSON is truncated", func() {
+				_, err := gomegamatchers.HelpfullyMatchJSON(animals).Match(`{"a": [1, 2`)
+				Expect(err.Error()).To(ContainSubstring("invalid JSON at line 1"))
+			})
+
+			It("returns an error when there is data after the JSON value", func() {
+				_, err := gomegamatchers.HelpfullyMatchJSON(animals).Match("{}\n  {}")
+				Expect(err.Error()).To(ContainSubstring("invalid JSON at line 2, column 3: unexpected data after top-level value"))
+			})
+		})
+	})
+
+	Describe("FailureMessage", func() {
+		It("provides localized error information", func() {
+			message := gomegamatchers.HelpfullyMatchJSON(`{"population": {"1980": {"absolute": 88314}}}`).
+				FailureMessage(`{"population": {"1980": {"absolute": 999999999}}}`)
+
+			Expect(message).To(ContainSubstring("error at [population][1980][absolute]:"))
+			Expect(message).To(ContainSubstring("  value mismatch:"))
+			Expect(message).To(ContainSubstring("        <int> 999999999"))
+			Expect(message).To(ContainSubstring("        <int> 88314"))
+		})
+
+		It("returns the error as the message", func() {
+			message := gomegamatchers.HelpfullyMatchJSON(animals).FailureMessage("{")
+			Expect(message).To(ContainSubstring("invalid JSON at line 1, column 1"))
+		})
+	})
+
+	Describe("NegatedFailureMessage", func() {
+		It("returns a negated failure message", func() {
+			message := gomegamatchers.HelpfullyMatchJSON(`{"a": 1}`).NegatedFailureMessage(`{"b": 2}`)
+			Expect(message).To(ContainSubstring("Expected"))
+			Expect(message).To(ContainSubstring(`"b": 2`))
+			Expect(message).To(ContainSubstring("not to match JSON of"))
+			Expect(message).To(ContainSubstring(`"a": 1`))
+		})
+	})
+})
@@ -2,12 +2,16 @@ package gomegamatchers
 
 import (
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/onsi/gomega/format"
 	"github.com/onsi/gomega/types"
 	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
 	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
 )
 
@@ -17,12 +21,117 @@ func HelpfullyMatchYAML(expected interface{}) types.GomegaMatcher {
 	}
 }
 
+// HelpfullyContainYAML succeeds when actual contains the expected YAML,
+// ignoring map keys that only appear in actual.
+func HelpfullyContainYAML(expected interface{}) types.GomegaMatcher {
+	return &HelpfullyMatchYAMLMatcher{
+		YAMLToMatch:     expected,
+		IgnoreExtraKeys: true,
+	}
+}
+
+// FailureFormat selects the sections of a HelpfullyMatchYAMLMatcher failure
+// message.
+type FailureFormat int
+
+const (
+	// LocalizedFailures lists each difference under "error at [path]".
+	LocalizedFailures FailureFormat = iota
+
+	// UnifiedDiff shows a unified diff of the normalized YAML documents.
+	UnifiedDiff
+
+	// LocalizedFailuresAndUnifiedDiff shows both sections.
+	LocalizedFailuresAndUnifiedDiff
+)
+
 type HelpfullyMatchYAMLMatcher struct {
+	// YAMLToMatch is YAML text, a document built in Go from maps and slices,
+	// or a struct with yaml tags. Actual YAML is decoded into the type of
+	// such a struct, failing on fields that it does not declare, and
+	// differences are reported under the yaml keys of its fields.
 	YAMLToMatch interface{}
+
+	// MaxDifferences caps the number of differences listed in the failure
+	// message. Zero lists every difference.
+	MaxDifferences int
+
+	// IgnoreExtraKeys treats YAMLToMatch as a recursive subset of actual.
+	IgnoreExtraKeys bool
+
+	// IgnoreOrder compares every sequence regardless of the order of its
+	// elements. IgnoreOrderPaths does so only for the sequences selected by
+	// patterns such as "[jobs][*][templates]".
+	IgnoreOrder      bool
+	IgnoreOrderPaths []string
+
+	// SliceKeys maps patterns such as "instance_groups[*]" to the field that
+	// identifies each element, such as "name". Elements of the selected
+	// sequences are paired by that field instead of by position, and
+	// failure messages show paths like [instance_groups][name=router].
+	SliceKeys map[string]string
+
+	// IgnorePaths skips the values selected by patterns such as
+	// "[jobs][*][properties][uuid]", whether they differ, are missing or are
+	// extra. AnyValuePaths only requires the selected keys to be present.
+	IgnorePaths   []string
+	AnyValuePaths []string
+
+	// FailureFormat selects how differences are shown in the failure
+	// message. DiffContext sets the number of unchanged lines around each
+	// change in a unified diff, and defaults to 3.
+	FailureFormat FailureFormat
+	DiffContext   int
+
+	// DocumentKeys pairs the documents of YAML streams by the values at
+	// these paths, such as "kind" and "[metadata][name]", instead of by
+	// their position in the stream.
+	DocumentKeys []string
+
+	// NumericValues compares numbers by value whatever their types, so that
+	// 8080 and 8080.0 match. Floats may also differ by up to
+	// AbsoluteTolerance, or by up to RelativeTolerance times the larger of
+	// the two. Setting either tolerance implies NumericValues.
+	NumericValues     bool
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+
+	// LooseScalars treats a string and a number or boolean with the same
+	// canonical string form as equal, so that 1.0 matches "1.0" and true
+	// matches "true". Two strings, such as '1.10' and '1.1', still have to
+	// be equal. When the match fails for other reasons, the scalars that
+	// only matched loosely are listed under "loose matches" so the drift
+	// stays visible.
+	LooseScalars bool
+
+	// PreserveAliases compares aliases such as *defaults, including those
+	// of "<<" merge keys, by the name of their anchor instead of expanding
+	// them. An alias only matches an alias of the same anchor.
+	PreserveAliases bool
+
+	// Strict fails the match with an error that locates any duplicate map
+	// key, which would otherwise silently take the last value.
+	// StringKeysOnly, on its own or together with Strict, rejects map keys
+	// that do not decode to strings, such as 1, true or on, which cannot be
+	// converted to JSON. Keys are resolved by the YAML 1.1 rules that
+	// decoding uses, so 1 and 01, or on and true, are duplicates.
+	Strict         bool
+	StringKeysOnly bool
+
+	// ReportFile and ReportWriter, when set, receive a JSON report of the
+	// differences each time a failure message is built, alongside it. The
+	// report lists the path of each difference as an array of segments, its
+	// kind, the expected and actual values, and where they appear in the
+	// YAML. ReportWriter may be GinkgoWriter, to keep the report with the
+	// output of the spec.
+	ReportFile   string
+	ReportWriter io.Writer
+
+	inputs documentInputs
 }
 
 func (matcher *HelpfullyMatchYAMLMatcher) Match(actual interface{}) (success bool, err error) {
-	equal, _, err := matcher.equal(matcher.YAMLToMatch, actual)
+	equal, _, err := matcher.equal(matcher.YAMLToMatch, actual, false)
 	if err != nil {
 		return false, err
 	}
@@ -31,7 +140,7 @@ func (matcher *HelpfullyMatchYAMLMatcher) Match(actual interface{}) (success boo
 }
 
 func (matcher *HelpfullyMatchYAMLMatcher) FailureMessage(actual interface{}) (message string) {
-	_, message, err := matcher.equal(matcher.YAMLToMatch, actual)
+	_, message, err := matcher.equal(matcher.YAMLToMatch, actual, true)
 	if err != nil {
 		return err.Error()
 	}
@@ -40,47 +149,423 @@ func (matcher *HelpfullyMatchYAMLMatcher) FailureMessage(actual interface{}) (me
 }
 
 func (matcher *HelpfullyMatchYAMLMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	actual, _, _ = matcher.inputs.readActual(actual)
+	expected, _, _ := matcher.inputs.readExpected(matcher.YAMLToMatch)
+
 	actualString, _ := matcher.prettyPrint(actual)
-	expectedString, _ := matcher.prettyPrint(matcher.YAMLToMatch)
+	expectedString, _ := matcher.prettyPrint(expected)
+	if matcher.IgnoreExtraKeys {
+		return format.Message(actualString, "not to contain YAML of", expectedString)
+	}
+
 	return format.Message(actualString, "not to match YAML of", expectedString)
 }
 
-func (matcher *HelpfullyMatchYAMLMatcher) equal(expected interface{}, actual interface{}) (bool, string, error) {
-	actualString, err := matcher.prettyPrint(actual)
+// equal compares expected with actual. Only with report, when the failure
+// message is asked for, does it build the message, including any unified
+// diff, and write the JSON report of the differences.
+func (matcher *HelpfullyMatchYAMLMatcher) equal(expected interface{}, actual interface{}, report bool) (bool, string, error) {
+	actual, actualFile, err := matcher.inputs.readActual(actual)
 	if err != nil {
 		return false, "", err
 	}
 
-	expectedString, err := matcher.prettyPrint(expected)
+	expected, expectedFile, err := matcher.inputs.readExpected(expected)
 	if err != nil {
 		return false, "", err
 	}
 
-	var actualValue interface{}
-	var expectedValue interface{}
+	if matcher.Strict || matcher.StringKeysOnly {
+		if err := checkKeys(actual, "actual YAML", matcher.Strict, matcher.StringKeysOnly); err != nil {
+			return false, "", inFile(actualFile, err)
+		}
 
-	// this is guarded by prettyPrint
-	yaml.Unmarshal([]byte(actualString), &actualValue)
-	yaml.Unmarshal([]byte(expectedString), &expectedValue)
+		if err := checkKeys(expected, "expected YAML", matcher.Strict, matcher.StringKeysOnly); err != nil {
+			return false, "", inFile(expectedFile, err)
+		}
+	}
 
-	equal, difference := deepequal.Compare(expectedValue, actualValue)
+	actualDocuments, err := matcher.unmarshalActual(expected, actual)
+	if err != nil {
+		return false, "", inFile(actualFile, err)
+	}
 
-	return equal, prettyprint.ExpectationFailure(difference), nil
+	expectedDocuments := []interface{}{yamlValue(expected)}
+	if !isDocument(expected) {
+		expectedDocuments, err = matcher.unmarshal(expected)
+		if err != nil {
+			return false, "", inFile(expectedFile, err)
+		}
+	}
+
+	options, err := matcher.options()
+	if err != nil {
+		return false, "", err
+	}
+
+	maxDifferences := options.MaxDifferences
+	if maxDifferences > 0 {
+		options.MaxDifferences = maxDifferences + 1
+	}
+
+	var looseMatches []diff.Difference
+	options.OnLooseMatch = func(looseMatch diff.Difference) {
+		looseMatches = append(looseMatches, looseMatch)
+	}
+
+	var differences []diff.Difference
+	if len(expectedDocuments) == 1 && len(actualDocuments) == 1 && len(matcher.DocumentKeys) == 0 {
+		differences = deepequal.CompareAll(expectedDocuments[0], actualDocuments[0], options)
+	} else {
+		differences, err = matcher.compareStreams(expectedDocuments, actualDocuments, options)
+		if err != nil {
+			return false, "", err
+		}
+	}
+
+	if len(differences) == 0 {
+		return true, "", nil
+	}
+
+	if !report {
+		return false, "", nil
+	}
+
+	expectedSource, actualSource := newYAMLSource(expected, expectedFile, true), newYAMLSource(actual, actualFile, false)
+	annotate := locateDifferences(expectedSource, actualSource)
+	message, err := matcher.failureMessage(expected, actual, renderDifferences(differences, maxDifferences, annotate))
+	if err != nil {
+		return false, "", err
+	}
+
+	if len(looseMatches) > 0 {
+		message += "\n\nloose matches (equal only by string form):\n\n" + prettyprint.Warnings(looseMatches, annotate, failureStyle())
+	}
+
+	if maxDifferences > 0 && len(differences) > maxDifferences {
+		differences = differences[:maxDifferences]
+	}
+
+	positions := positionDifferences(expectedSource, actualSource)
+	if err := matcher.writeReport(diff.NewReport(differences, positions)); err != nil {
+		message += "\n\nfailed to write the difference report: " + err.Error()
+	}
+
+	return false, fixtureNames(expectedFile, actualFile) + message, nil
 }
 
-func (matcher *HelpfullyMatchYAMLMatcher) prettyPrint(input interface{}) (formatted string, err error) {
+// failureMessage lays out the localized failures and the unified diff as
+// FailureFormat asks.
+func (matcher *HelpfullyMatchYAMLMatcher) failureMessage(expected interface{}, actual interface{}, failures string) (string, error) {
+	if matcher.FailureFormat == LocalizedFailures {
+		return failures, nil
+	}
+
+	unifiedDiff, err := matcher.unifiedDiff(expected, actual)
+	if err != nil {
+		return "", err
+	}
+
+	if matcher.FailureFormat == UnifiedDiff {
+		return unifiedDiff, nil
+	}
+
+	return failures + "\n\n" + unifiedDiff, nil
+}
+
+func (matcher *HelpfullyMatchYAMLMatcher) unifiedDiff(expected interface{}, actual interface{}) (string, error) {
+	if isStruct(expected) {
+		documents, err := matcher.unmarshalActual(expected, actual)
+		if err != nil {
+			return "", err
+		}
+
+		actual = documents[0]
+	}
+
+	actualString, err := matcher.prettyPrint(actual)
+	if err != nil {
+		return "", err
+	}
+
+	expectedString, err := matcher.prettyPrint(expected)
+	if err != nil {
+		return "", err
+	}
+
+	context := matcher.DiffContext
+	if context == 0 {
+		context = 3
+	}
+
+	unifiedDiff := prettyprint.UnifiedDiff(expectedString, actualString, context)
+	if unifiedDiff == "" {
+		return "unified diff: the normalized documents are identical", nil
+	}
+
+	return "unified diff (- expected, + actual):\n" + unifiedDiff, nil
+}
+
+// unmarshal returns every document of a YAML stream. An empty stream holds
+// a single nil document.
+func (matcher *HelpfullyMatchYAMLMatcher) unmarshal(input interface{}) ([]interface{}, error) {
 	inputString, ok := toString(input)
 	if !ok {
-		return "", fmt.Errorf("HelpfullyMatchYAMLMatcher matcher requires a string or stringer.  Got:\n%s", format.Object(input, 1))
+		return nil, fmt.Errorf("HelpfullyMatchYAMLMatcher matcher requires a string, stringer, io.Reader or File.  Got:\n%s", format.Object(input, 1))
+	}
+
+	if matcher.PreserveAliases {
+		return unmarshalPreservingAliases(inputString)
+	}
+
+	var documents []interface{}
+
+	decoder := yaml.NewDecoder(strings.NewReader(inputString))
+	for {
+		var document interface{}
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return withoutEmptyDocuments(documents), nil
+}
+
+// unmarshalActual decodes actual into a new value of the type of expected
+// when expected is a struct, and into generic documents otherwise. Fields
+// that the struct does not declare are rejected unless IgnoreExtraKeys is
+// set.
+func (matcher *HelpfullyMatchYAMLMatcher) unmarshalActual(expected interface{}, actual interface{}) ([]interface{}, error) {
+	if !isStruct(expected) {
+		return matcher.unmarshal(actual)
+	}
+
+	actualString, ok := toString(actual)
+	if !ok {
+		return nil, fmt.Errorf("HelpfullyMatchYAMLMatcher matcher requires a string, stringer, io.Reader or File.  Got:\n%s", format.Object(actual, 1))
+	}
+
+	structType := reflect.TypeOf(expected)
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	unmarshal := yaml.UnmarshalStrict
+	if matcher.IgnoreExtraKeys {
+		unmarshal = yaml.Unmarshal
+	}
+
+	value := reflect.New(structType)
+	if err := unmarshal([]byte(actualString), value.Interface()); err != nil {
+		return nil, err
+	}
+
+	if reflect.TypeOf(expected).Kind() == reflect.Ptr {
+		return []interface{}{value.Interface()}, nil
 	}
 
-	var data interface{}
-	if err := yaml.Unmarshal([]byte(inputString), &data); err != nil {
+	return []interface{}{value.Elem().Interface()}, nil
+}
+
+// withoutEmptyDocuments drops the empty documents that stray "---" lines
+// leave in a stream, keeping a single nil document when nothing else is left.
+func withoutEmptyDocuments(documents []interface{}) []interface{} {
+	var nonEmpty []interface{}
+	for _, document := range documents {
+		if document != nil {
+			nonEmpty = append(nonEmpty, document)
+		}
+	}
+
+	if len(nonEmpty) == 0 {
+		return []interface{}{nil}
+	}
+
+	if len(documents) == 1 {
+		return documents
+	}
+
+	return nonEmpty
+}
+
+func (matcher *HelpfullyMatchYAMLMatcher) options() (deepequal.Options, error) {
+	return deepequal.Options{
+		MaxDifferences:  matcher.MaxDifferences,
+		IgnoreExtraKeys: matcher.IgnoreExtraKeys,
+		IgnoreOrder:     matcher.IgnoreOrder,
+		FieldTag:        "yaml",
+
+		NumericValues:     matcher.NumericValues,
+		AbsoluteTolerance: matcher.AbsoluteTolerance,
+		RelativeTolerance: matcher.RelativeTolerance,
+
+		LooseScalars: matcher.LooseScalars,
+	}.WithPaths(deepequal.PathOptions{
+		IgnoreOrderPaths: matcher.IgnoreOrderPaths,
+		SliceKeys:        matcher.SliceKeys,
+		IgnorePaths:      matcher.IgnorePaths,
+		AnyValuePaths:    matcher.AnyValuePaths,
+	})
+}
+
+func (matcher *HelpfullyMatchYAMLMatcher) prettyPrint(input interface{}) (formatted string, err error) {
+	if isDocument(input) {
+		buf, err := yaml.Marshal(printableValue(yamlValue(input)))
+		return string(buf), err
+	}
+
+	documents, err := matcher.unmarshal(input)
+	if err != nil {
 		return "", err
 	}
-	buf, _ := yaml.Marshal(data)
 
-	return string(buf), nil
+	var formattedDocuments []string
+	for _, document := range documents {
+		buf, _ := yaml.Marshal(document)
+		formattedDocuments = append(formattedDocuments, string(buf))
+	}
+
+	return strings.Join(formattedDocuments, "---\n"), nil
+}
+
+// isDocument reports whether input is an expected document built in Go,
+// from maps and slices whose leaves may be gomega matchers, or a struct
+// with yaml tags, rather than YAML text.
+func isDocument(input interface{}) bool {
+	if _, ok := toString(input); ok {
+		return false
+	}
+
+	if _, ok := input.(types.GomegaMatcher); ok {
+		return true
+	}
+
+	if isStruct(input) {
+		return true
+	}
+
+	switch reflect.ValueOf(input).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// isStruct reports whether input is a struct, or a pointer to one, that
+// actual YAML should be decoded into.
+func isStruct(input interface{}) bool {
+	if _, ok := toString(input); ok {
+		return false
+	}
+
+	if _, ok := input.(types.GomegaMatcher); ok {
+		return false
+	}
+
+	inputType := reflect.TypeOf(input)
+	if inputType == nil {
+		return false
+	}
+
+	if inputType.Kind() == reflect.Ptr {
+		inputType = inputType.Elem()
+	}
+
+	return inputType.Kind() == reflect.Struct
+}
+
+// yamlValue converts a document built in Go into the shapes that
+// yaml.Unmarshal produces, leaving gomega matchers in place.
+func yamlValue(input interface{}) interface{} {
+	if _, ok := input.(types.GomegaMatcher); ok {
+		return input
+	}
+
+	value := reflect.ValueOf(input)
+	switch value.Kind() {
+	case reflect.Map:
+		converted := map[interface{}]interface{}{}
+		for _, key := range value.MapKeys() {
+			converted[key.Interface()] = yamlValue(value.MapIndex(key).Interface())
+		}
+		return converted
+
+	case reflect.Slice, reflect.Array:
+		if _, ok := input.([]byte); ok {
+			return input
+		}
+
+		converted := []interface{}{}
+		for i := 0; i < value.Len(); i++ {
+			converted = append(converted, yamlValue(value.Index(i).Interface()))
+		}
+		return converted
+
+	default:
+		return input
+	}
+}
+
+// printableValue replaces the gomega matchers left in a document by
+// yamlValue with their type names, since yaml.Marshal would print their
+// fields, or panic on the funcs of matchers such as WithTransform.
+func printableValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case types.GomegaMatcher:
+		return fmt.Sprintf("<%T>", value)
+
+	case map[interface{}]interface{}:
+		converted := map[interface{}]interface{}{}
+		for key, element := range value {
+			converted[key] = printableValue(element)
+		}
+		return converted
+
+	case []interface{}:
+		converted := []interface{}{}
+		for _, element := range value {
+			converted = append(converted, printableValue(element))
+		}
+		return converted
+
+	default:
+		return value
+	}
+}
+
+// compareDocuments compares two unmarshalled documents and renders every
+// difference, listing at most options.MaxDifferences of them when it is
+// positive. annotate, when not nil, adds a note to each difference.
+func compareDocuments(expectedValue interface{}, actualValue interface{}, options deepequal.Options, annotate func(diff.Difference) string) (bool, string) {
+	maxDifferences := options.MaxDifferences
+	if maxDifferences > 0 {
+		options.MaxDifferences = maxDifferences + 1
+	}
+
+	differences := deepequal.CompareAll(expectedValue, actualValue, options)
+	if len(differences) == 0 {
+		return true, ""
+	}
+
+	return false, renderDifferences(differences, maxDifferences, annotate)
+}
+
+// renderDifferences lists at most maxDifferences differences, when it is
+// positive, and notes when some were left out.
+func renderDifferences(differences []diff.Difference, maxDifferences int, annotate func(diff.Difference) string) string {
+	if maxDifferences > 0 && len(differences) > maxDifferences {
+		message := prettyprint.ExpectationFailures(differences[:maxDifferences], annotate, failureStyle())
+		return message + fmt.Sprintf("\n\nstopped after %d differences", maxDifferences)
+	}
+
+	return prettyprint.ExpectationFailures(differences, annotate, failureStyle())
 }
 
 func toString(value interface{}) (string, bool) {
@@ -1,7 +1,12 @@
 package gomegamatchers_test
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,6 +21,48 @@ func (a animalStringer) String() string {
 	return a.Data
 }
 
+// sliceReader is an io.Reader whose type is not comparable.
+type sliceReader struct {
+	data   []byte
+	offset *int
+}
+
+func (r sliceReader) Read(p []byte) (int, error) {
+	if *r.offset >= len(r.data) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.data[*r.offset:])
+	*r.offset += n
+	return n, nil
+}
+
+// funcReader is an io.Reader that reflect.DeepEqual cannot compare.
+type funcReader struct {
+	read func([]byte) (int, error)
+}
+
+func (r funcReader) Read(p []byte) (int, error) {
+	return r.read(p)
+}
+
+type deploymentContainer struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+}
+
+type deploymentLabels struct {
+	Tier string `yaml:"tier,omitempty"`
+}
+
+type deployment struct {
+	Name       string                `yaml:"name"`
+	Replicas   int                   `yaml:"replicas,omitempty"`
+	Containers []deploymentContainer `yaml:"containers"`
+	Labels     deploymentLabels      `yaml:",inline"`
+	Internal   string                `yaml:"-"`
+}
+
 var _ = Describe("HelpfullyMatchYAMLMatcher", func() {
 	var animals, plants string
 
@@ -90,10 +137,564 @@ var _ = Describe("HelpfullyMatchYAMLMatcher", func() {
 			})
 		})
 
+		Context("when an input is an io.Reader", func() {
+			It("reads the reader only once across Match and FailureMessage", func() {
+				matcher := gomegamatchers.HelpfullyMatchYAML(animals)
+				actual := strings.NewReader(plants)
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				Expect(matcher.FailureMessage(actual)).To(ContainSubstring("error at "))
+			})
+
+			It("remembers the contents of a reader whose type is not comparable", func() {
+				matcher := gomegamatchers.HelpfullyMatchYAML(animals)
+				actual := sliceReader{data: []byte(plants), offset: new(int)}
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				message := matcher.FailureMessage(actual)
+				Expect(message).To(ContainSubstring("error at "))
+				Expect(message).NotTo(ContainSubstring("<nil>"))
+			})
+
+			It("remembers the contents of any reader across Match and FailureMessage", func() {
+				matcher := gomegamatchers.HelpfullyMatchYAML(animals)
+				actual := funcReader{read: strings.NewReader(plants).Read}
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				message := matcher.FailureMessage(actual)
+				Expect(message).To(ContainSubstring("error at "))
+				Expect(message).NotTo(ContainSubstring("<nil>"))
+			})
+
+			It("reads a new reader of a comparable type", func() {
+				matcher := gomegamatchers.HelpfullyMatchYAML(animals)
+
+				isMatch, err := matcher.Match(strings.NewReader(plants))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				isMatch, err = matcher.Match(strings.NewReader(animals))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+		})
+
+		Context("when an input is a File", func() {
+			It("compares the contents of the files", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchYAML(gomegamatchers.FromFile("fixtures/santa_monica_correct.yml")).Match(gomegamatchers.FromFile("fixtures/santa_monica_correct.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("returns an error when a file cannot be read", func() {
+				_, err := gomegamatchers.HelpfullyMatchYAML(gomegamatchers.FromFile("fixtures/missing.yml")).Match("a: 1")
+				Expect(err).To(MatchError(ContainSubstring("open fixtures/missing.yml")))
+			})
+
+			It("names the file when its YAML is invalid", func() {
+				file, err := ioutil.TempFile("", "invalid-*.yml")
+				Expect(err).NotTo(HaveOccurred())
+				defer os.Remove(file.Name())
+
+				_, err = file.WriteString("some: invalid: yaml")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+
+				_, err = gomegamatchers.HelpfullyMatchYAML(animals).Match(gomegamatchers.FromFile(file.Name()))
+				Expect(err).To(MatchError(HavePrefix(file.Name() + ": yaml: ")))
+			})
+		})
+
+		Context("when ignoring sequence order", func() {
+			var expected, actual string
+
+			BeforeEach(func() {
+				expected = "releases: [cf, diego]\nstemcells: [trusty, xenial]"
+				actual = "releases: [diego, cf]\nstemcells: [xenial, trusty]"
+			})
+
+			It("returns true when every sequence has the same elements", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: expected,
+					IgnoreOrder: true,
+				}
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("only ignores the order of the selected sequences", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:      expected,
+					IgnoreOrderPaths: []string{"releases"},
+				}
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				message := matcher.FailureMessage(actual)
+				Expect(message).To(ContainSubstring("error at [stemcells][0]:"))
+				Expect(message).NotTo(ContainSubstring("error at [releases]"))
+			})
+
+			It("reports the unmatched elements", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: expected,
+					IgnoreOrder: true,
+				}
+
+				message := matcher.FailureMessage("releases: [routing, cf]\nstemcells: [xenial, trusty]")
+				Expect(message).To(ContainSubstring("error at [releases]:\n  unmatched elements (ignoring order):"))
+				Expect(message).To(ContainSubstring("        [<string> diego]"))
+				Expect(message).To(ContainSubstring("        [<string> routing]"))
+			})
+
+			It("returns an error when a path pattern is invalid", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:      expected,
+					IgnoreOrderPaths: []string{"[releases"},
+				}
+
+				_, err := matcher.Match(actual)
+				Expect(err).To(MatchError(`invalid path pattern "[releases": missing ']'`))
+			})
+		})
+
+		Context("when sequences are keyed by a field", func() {
+			var matcher *gomegamatchers.HelpfullyMatchYAMLMatcher
+
+			BeforeEach(func() {
+				matcher = &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: `instance_groups:
+- name: router
+  jobs:
+  - name: gorouter
+    properties: {port: 80}
+- name: api`,
+					SliceKeys: map[string]string{
+						"instance_groups[*]":          "name",
+						"instance_groups[*][jobs][*]": "name",
+					},
+				}
+			})
+
+			It("returns true when an element moved", func() {
+				isMatch, err := matcher.Match(`instance_groups:
+- name: api
+- name: router
+  jobs:
+  - name: gorouter
+    properties: {port: 80}`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("reports differences by element key", func() {
+				message := matcher.FailureMessage(`instance_groups:
+- name: uaa
+- name: router
+  jobs:
+  - name: gorouter
+    properties: {port: 8080}
+- name: api`)
+				Expect(message).To(ContainSubstring("error at [instance_groups][name=router][jobs][name=gorouter][properties][port]:"))
+				Expect(message).To(ContainSubstring("error at [instance_groups]:\n  extra element found:"))
+				Expect(message).To(ContainSubstring("    not to contain element with name\n        <string> uaa"))
+			})
+		})
+
+		Context("when paths are ignored", func() {
+			var expected string
+
+			BeforeEach(func() {
+				expected = "name: cf\nuuid: 1111\nproperties:\n  password: secret\n  port: 80"
+			})
+
+			It("skips the values at IgnorePaths", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: expected,
+					IgnorePaths: []string{"uuid", "[properties][*]"},
+				}
+
+				isMatch, err := matcher.Match("name: cf\nproperties:\n  password: other\n  host: example.com")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("only requires the keys at AnyValuePaths to be present", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:   expected,
+					AnyValuePaths: []string{"uuid", "[properties][password]"},
+				}
+
+				isMatch, err := matcher.Match("name: cf\nuuid: 2222\nproperties:\n  password: other\n  port: 80")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				message := matcher.FailureMessage("name: cf\nproperties:\n  password: other\n  port: 80")
+				Expect(message).To(ContainSubstring("error at :\n  missing key:"))
+				Expect(message).To(ContainSubstring("    to contain key\n        <string> uuid"))
+			})
+		})
+
+		Context("when the expected YAML is a Go structure with embedded matchers", func() {
+			var expected map[string]interface{}
+
+			BeforeEach(func() {
+				expected = map[string]interface{}{
+					"name": "cf",
+					"properties": map[string]interface{}{
+						"password": HaveLen(32),
+						"port":     BeNumerically(">", 1024),
+						"hosts":    []interface{}{MatchRegexp(`\.example\.com$`), "localhost"},
+					},
+				}
+			})
+
+			It("returns true when every matcher succeeds", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchYAML(expected).Match(`
+name: cf
+properties:
+  password: 0123456789abcdef0123456789abcdef
+  port: 8080
+  hosts: [api.example.com, localhost]`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("reports the matcher failure message at its path", func() {
+				message := gomegamatchers.HelpfullyMatchYAML(expected).FailureMessage(`
+name: cf
+properties:
+  password: short
+  port: 8080
+  hosts: [api.example.org, localhost]`)
+				Expect(message).To(ContainSubstring("error at [properties][password]:\n  matcher failed:"))
+				Expect(message).To(ContainSubstring("        <string>: short"))
+				Expect(message).To(ContainSubstring("error at [properties][hosts][0]:\n  matcher failed:"))
+			})
+
+			It("still reports structural differences", func() {
+				message := gomegamatchers.HelpfullyMatchYAML(expected).FailureMessage("name: diego")
+				Expect(message).To(ContainSubstring("error at [name]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("error at :\n  missing key:"))
+			})
+		})
+
+		Context("when numbers are compared by value", func() {
+			It("matches integers and floats with the same value", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:   "port: 8080\nratio: 1",
+					NumericValues: true,
+				}
+
+				isMatch, err := matcher.Match("port: 8080.0\nratio: 1.0")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("matches floats within the tolerances", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:       "cpu: 0.3\nmemory: 1000",
+					AbsoluteTolerance: 0.01,
+				}
+
+				isMatch, err := matcher.Match("cpu: 0.30000000000000004\nmemory: 1000.005")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				matcher = &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:       "memory: 1000",
+					RelativeTolerance: 0.001,
+				}
+
+				isMatch, err = matcher.Match("memory: 1000.9")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				isMatch, err = matcher.Match("memory: 1002")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+			})
+
+			It("reports a value mismatch when the numbers differ", func() {
+				message := (&gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:   "port: 8080",
+					NumericValues: true,
+				}).FailureMessage("port: 8081.0")
+				Expect(message).To(ContainSubstring("error at [port]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("<float64> 8081"))
+			})
+		})
+
+		Context("when scalars are compared loosely", func() {
+			It("matches scalars with the same string form", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:  "version: 1.0\nenabled: \"true\"",
+					LooseScalars: true,
+				}
+
+				isMatch, err := matcher.Match("version: \"1.0\"\nenabled: true")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("still compares strings exactly", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:  "version: '1.10'",
+					LooseScalars: true,
+				}
+
+				isMatch, err := matcher.Match("version: '1.1'")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+			})
+
+			It("lists loose matches separately when the match fails", func() {
+				message := (&gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:  "version: 1.0\nname: web",
+					LooseScalars: true,
+				}).FailureMessage("version: \"1.0\"\nname: api")
+
+				Expect(message).To(HavePrefix("error at [name]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("\n\nloose matches (equal only by string form):\n\nwarning at [version]:\n  loose match:"))
+				Expect(message).To(ContainSubstring(`only by its string form "1"`))
+				Expect(message).To(ContainSubstring("  location: actual line 1, col 10 / expected line 1, col 10"))
+			})
+
+			It("names the document of loose matches in streams", func() {
+				message := (&gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:  "name: a\n---\nversion: 2\n",
+					LooseScalars: true,
+				}).FailureMessage("name: b\n---\nversion: \"2\"\n")
+
+				Expect(message).To(ContainSubstring("warning at [document 1][version]:"))
+			})
+		})
+
+		Context("when aliases are preserved", func() {
+			var matcher *gomegamatchers.HelpfullyMatchYAMLMatcher
+
+			BeforeEach(func() {
+				matcher = &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:     "defaults: &defaults\n  port: 80\nweb:\n  <<: *defaults\n  name: web",
+					PreserveAliases: true,
+				}
+			})
+
+			It("matches aliases of the same anchor", func() {
+				isMatch, err := matcher.Match("defaults: &defaults\n  port: 80\nweb:\n  name: web\n  <<: *defaults")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("resolves scalars the same way as without PreserveAliases", func() {
+				pairs := [][2]string{
+					{"a: true", "a: yes"},
+					{"a: false", "a: off"},
+					{"a: 511", "a: 0777"},
+					{"a: 'yes'", "a: !!str yes"},
+					{"a: true", "a: 'yes'"},
+				}
+
+				for _, pair := range pairs {
+					for _, preserveAliases := range []bool{false, true} {
+						isMatch, err := (&gomegamatchers.HelpfullyMatchYAMLMatcher{
+							YAMLToMatch:     pair[0],
+							PreserveAliases: preserveAliases,
+						}).Match(pair[1])
+						Expect(err).NotTo(HaveOccurred())
+						Expect(isMatch).To(Equal(pair[1] != "a: 'yes'"), "%s against %s with PreserveAliases %t", pair[1], pair[0], preserveAliases)
+					}
+				}
+			})
+
+			It("does not match the expanded values", func() {
+				isMatch, err := matcher.Match("defaults: &defaults\n  port: 80\nweb:\n  port: 80\n  name: web")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				message := matcher.FailureMessage("defaults: &defaults\n  port: 80\nweb:\n  port: 80\n  name: web")
+				Expect(message).To(ContainSubstring("error at [web]:\n  missing key:"))
+				Expect(message).To(ContainSubstring("to contain key\n        <string> <<"))
+			})
+
+			It("reports aliases of different anchors", func() {
+				message := (&gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:     "a: &a 1\nb: &b 2\nc: *a",
+					PreserveAliases: true,
+				}).FailureMessage("a: &a 1\nb: &b 2\nc: *b")
+				Expect(message).To(ContainSubstring("error at [c]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("<gomegamatchers.yamlAlias> *b"))
+			})
+		})
+
+		Context("when the expected YAML is a struct with yaml tags", func() {
+			It("matches YAML that decodes to an equal value", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchYAML(deployment{
+					Name:     "web",
+					Replicas: 2,
+					Containers: []deploymentContainer{
+						{Name: "app", Image: "app:1.0"},
+					},
+				}).Match("name: web\nreplicas: 2\ncontainers:\n- name: app\n  image: app:1.0")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("returns an error for fields the struct does not declare", func() {
+				_, err := gomegamatchers.HelpfullyMatchYAML(&deployment{Name: "web"}).Match("name: web\nreplica: 2")
+				Expect(err).To(MatchError(ContainSubstring("line 2: field replica not found in type gomegamatchers_test.deployment")))
+			})
+
+			It("allows undeclared fields when containing YAML", func() {
+				isMatch, err := gomegamatchers.HelpfullyContainYAML(&deployment{Name: "web"}).Match("name: web\nreplica: 2")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("reports differences under the yaml keys of the fields", func() {
+				message := gomegamatchers.HelpfullyMatchYAML(deployment{
+					Name: "web",
+					Containers: []deploymentContainer{
+						{Name: "app", Image: "app:1.0"},
+					},
+					Labels: deploymentLabels{Tier: "frontend"},
+				}).FailureMessage("name: web\ntier: backend\ncontainers:\n- name: app\n  image: app:2.0")
+
+				Expect(message).To(ContainSubstring("error at [containers][0][image]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("  location: actual line 5, col 10\n"))
+				Expect(message).To(ContainSubstring("error at [tier]:\n  value mismatch:"))
+				Expect(message).NotTo(ContainSubstring("Internal"))
+			})
+		})
+
+		Context("when the YAML is a stream of documents", func() {
+			var stream string
+
+			BeforeEach(func() {
+				stream = `---
+kind: Deployment
+metadata: {name: web}
+spec: {replicas: 2}
+---
+kind: Service
+metadata: {name: web}
+spec: {port: 80}
+`
+			})
+
+			It("returns true when every document matches", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchYAML(stream).Match(stream)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("compares every document, not only the first", func() {
+				matcher := gomegamatchers.HelpfullyMatchYAML(stream)
+				actual := strings.Replace(stream, "port: 80", "port: 8080", 1)
+
+				isMatch, err := matcher.Match(actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				message := matcher.FailureMessage(actual)
+				Expect(message).To(ContainSubstring("error at [document 1][spec][port]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("  location: actual line 8, col 14 / expected line 8, col 14"))
+			})
+
+			It("ignores empty documents left by stray separators", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchYAML(stream).Match(stream + "---\n---\n")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				isMatch, err = gomegamatchers.HelpfullyMatchYAML("a: 1").Match("a: 1\n---\n")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("reports missing documents", func() {
+				message := gomegamatchers.HelpfullyMatchYAML(stream).FailureMessage("kind: Deployment\nmetadata: {name: web}\nspec: {replicas: 2}")
+				Expect(message).To(ContainSubstring("error at :\n  missing document:"))
+				Expect(message).To(ContainSubstring("    Expected\n        [document 0]\n    to contain\n        document 1"))
+			})
+
+			It("locates extra and missing documents only on the side that has them", func() {
+				message := gomegamatchers.HelpfullyMatchYAML(stream).FailureMessage(stream + "---\nkind: ConfigMap\n")
+				Expect(message).To(ContainSubstring("  extra document found:"))
+				Expect(message).To(HaveSuffix("  location: actual line 10, col 1"))
+
+				message = gomegamatchers.HelpfullyMatchYAML(stream + "---\nkind: ConfigMap\n").FailureMessage(stream)
+				Expect(message).To(ContainSubstring("  missing document:"))
+				Expect(message).To(HaveSuffix("  location: expected line 10, col 1"))
+			})
+
+			Context("when documents are identified by DocumentKeys", func() {
+				var matcher *gomegamatchers.HelpfullyMatchYAMLMatcher
+
+				BeforeEach(func() {
+					matcher = &gomegamatchers.HelpfullyMatchYAMLMatcher{
+						YAMLToMatch:  stream,
+						DocumentKeys: []string{"kind", "[metadata][name]"},
+					}
+				})
+
+				It("pairs documents by identity regardless of their order", func() {
+					isMatch, err := matcher.Match(`
+kind: Service
+metadata: {name: web}
+spec: {port: 80}
+---
+kind: Deployment
+metadata: {name: web}
+spec: {replicas: 2}`)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(isMatch).To(BeTrue())
+				})
+
+				It("prefixes paths with the document identity", func() {
+					message := matcher.FailureMessage(`
+kind: Service
+metadata: {name: web}
+spec: {port: 8080}
+---
+kind: Deployment
+metadata: {name: api}
+spec: {replicas: 2}`)
+					Expect(message).To(ContainSubstring("error at [document kind=Service,[metadata][name]=web][spec][port]:"))
+					Expect(message).To(ContainSubstring("  extra document found:"))
+					Expect(message).To(ContainSubstring("        document kind=Deployment,[metadata][name]=api"))
+					Expect(message).To(ContainSubstring("  missing document:"))
+					Expect(message).To(ContainSubstring("        document kind=Deployment,[metadata][name]=web"))
+				})
+
+				It("returns an error when a document has no identity", func() {
+					_, err := matcher.Match("kind: Service")
+					Expect(err).To(MatchError("document 0: no value at [metadata][name] to identify it"))
+				})
+
+				It("returns an error when two documents share an identity", func() {
+					_, err := matcher.Match(stream + "---\n" + "kind: Service\nmetadata: {name: web}")
+					Expect(err).To(MatchError("document 2: another document is also identified by kind=Service,[metadata][name]=web"))
+				})
+			})
+		})
+
 		Describe("errors", func() {
 			It("returns an error when one of the inputs is not a string, byte slice, or Stringer", func() {
 				_, err := gomegamatchers.HelpfullyMatchYAML(animals).Match(123213)
-				Expect(err.Error()).To(ContainSubstring("HelpfullyMatchYAMLMatcher matcher requires a string or stringer."))
+				Expect(err.Error()).To(ContainSubstring("HelpfullyMatchYAMLMatcher matcher requires a string, stringer, io.Reader or File."))
 				Expect(err.Error()).To(ContainSubstring("Got:\n    <int>: 123213"))
 			})
 
@@ -101,6 +702,88 @@ var _ = Describe("HelpfullyMatchYAMLMatcher", func() {
 				_, err := gomegamatchers.HelpfullyMatchYAML(animals).Match("some: invalid: yaml")
 				Expect(err.Error()).To(ContainSubstring("mapping values are not allowed in this context"))
 			})
+
+			It("takes the last of duplicate keys unless strict", func() {
+				isMatch, err := gomegamatchers.HelpfullyMatchYAML("properties: {b: 2}").Match("properties: {a: 1}\nproperties: {b: 2}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("returns a located error for duplicate keys in strict mode", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: "properties: {b: 2}",
+					Strict:      true,
+				}
+
+				_, err := matcher.Match("name: web\nproperties: {a: 1}\nproperties: {b: 2}")
+				Expect(err).To(MatchError(`duplicate key "properties" in actual YAML at line 3, col 1, first defined at line 2, col 1`))
+
+				_, err = matcher.Match("jobs:\n- name: a\n  port: 1\n  port: 2\n---\nother: doc")
+				Expect(err).To(MatchError(`duplicate key "port" in actual YAML at line 4, col 3, first defined at line 3, col 3`))
+
+				matcher.YAMLToMatch = "a: 1\na: 1"
+				_, err = matcher.Match("a: 1")
+				Expect(err).To(MatchError(ContainSubstring(`duplicate key "a" in expected YAML at line 2, col 1`)))
+			})
+
+			It("finds duplicate keys that YAML 1.1 resolves to the same value", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: "x: {1: b}",
+					Strict:      true,
+				}
+
+				_, err := matcher.Match("x: {1: a, 01: b}")
+				Expect(err).To(MatchError(`duplicate key "01" in actual YAML at line 1, col 11, first defined at line 1, col 5`))
+
+				matcher.YAMLToMatch = "true: b"
+				_, err = matcher.Match("{on: a, true: b}")
+				Expect(err).To(MatchError(`duplicate key "true" in actual YAML at line 1, col 9, first defined at line 1, col 2`))
+			})
+
+			It("does not treat keys of different types as duplicates", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: "1: a\n\"1\": b",
+					Strict:      true,
+				}
+
+				isMatch, err := matcher.Match("1: a\n\"1\": b")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+			})
+
+			It("returns a located error for non-string keys when asked to", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:    "ports: {http: 80}",
+					StringKeysOnly: true,
+				}
+
+				_, err := matcher.Match("ports:\n  http: 80\n  443: https")
+				Expect(err).To(MatchError("non-string key 443 (int) in actual YAML at line 3, col 3"))
+
+				message := matcher.FailureMessage("ports:\n  true: 80")
+				Expect(message).To(Equal("non-string key true (bool) in actual YAML at line 2, col 3"))
+
+				_, err = matcher.Match("ports:\n  on: 80")
+				Expect(err).To(MatchError("non-string key on (bool) in actual YAML at line 2, col 3"))
+
+				_, err = matcher.Match("yes: 1")
+				Expect(err).To(MatchError("non-string key yes (bool) in actual YAML at line 1, col 1"))
+			})
+
+			It("leaves duplicate keys alone unless in strict mode", func() {
+				matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:    "ports: {http: 80}",
+					StringKeysOnly: true,
+				}
+
+				isMatch, err := matcher.Match("ports: {http: 8080}\nports: {http: 80}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeTrue())
+
+				matcher.Strict = true
+				_, err = matcher.Match("ports: {http: 8080}\nports: {http: 80}")
+				Expect(err).To(MatchError(ContainSubstring(`duplicate key "ports"`)))
+			})
 		})
 	})
 
@@ -160,6 +843,253 @@ var _ = Describe("HelpfullyMatchYAMLMatcher", func() {
 				))
 		})
 
+		It("reports every difference in the document", func() {
+			correctYAML, err := ioutil.ReadFile("fixtures/santa_monica_correct.yml")
+			Expect(err).NotTo(HaveOccurred())
+
+			incorrectYAML, err := ioutil.ReadFile("fixtures/santa_monica_incorrect.yml")
+			Expect(err).NotTo(HaveOccurred())
+
+			message := gomegamatchers.HelpfullyMatchYAML(correctYAML).FailureMessage(incorrectYAML)
+			Expect(message).To(ContainSubstring("error at [population][1980][absolute]:"))
+			Expect(message).To(ContainSubstring("error at [population][1990]:\n  extra key found:"))
+			Expect(message).To(ContainSubstring("error at [population][1990]:\n  missing key:"))
+			Expect(message).To(ContainSubstring("error at [population][2000][absolute]:"))
+		})
+
+		It("reports where each difference is in the actual and expected YAML", func() {
+			correctYAML, err := ioutil.ReadFile("fixtures/santa_monica_correct.yml")
+			Expect(err).NotTo(HaveOccurred())
+
+			incorrectYAML, err := ioutil.ReadFile("fixtures/santa_monica_incorrect.yml")
+			Expect(err).NotTo(HaveOccurred())
+
+			message := gomegamatchers.HelpfullyMatchYAML(correctYAML).FailureMessage(animalStringer{Data: string(incorrectYAML)})
+			Expect(message).To(ContainSubstring("        <int> 88314\n  location: actual line 5, col 20 / expected line 5, col 20"))
+			Expect(message).To(ContainSubstring("        <string> wrong_key\n  location: actual line 6, col 10 / expected line 6, col 9"))
+			Expect(message).To(ContainSubstring("        <string> absolute\n  location: actual line 6, col 9 / expected line 6, col 10"))
+		})
+
+		It("locates differences under keys that YAML 1.1 resolves", func() {
+			message := gomegamatchers.HelpfullyMatchYAML("on: {01: a}").FailureMessage("on: {01: b}")
+			Expect(message).To(ContainSubstring("error at [true][1]:"))
+			Expect(message).To(ContainSubstring("  location: actual line 1, col 10 / expected line 1, col 10"))
+		})
+
+		It("names the files that were compared", func() {
+			message := gomegamatchers.HelpfullyMatchYAML(gomegamatchers.FromFile("fixtures/santa_monica_correct.yml")).FailureMessage(gomegamatchers.FromFile("fixtures/santa_monica_incorrect.yml"))
+			Expect(message).To(HavePrefix("comparing actual fixtures/santa_monica_incorrect.yml to expected fixtures/santa_monica_correct.yml\n\nerror at "))
+			Expect(message).To(ContainSubstring("        <int> 88314\n  location: actual line 5, col 20 in fixtures/santa_monica_incorrect.yml / expected line 5, col 20 in fixtures/santa_monica_correct.yml"))
+		})
+
+		It("follows keyed elements and merge keys when locating differences", func() {
+			matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+				YAMLToMatch: "defaults: &defaults\n  port: 80\njobs:\n- name: router\n  <<: *defaults",
+				SliceKeys:   map[string]string{"jobs[*]": "name"},
+			}
+
+			message := matcher.FailureMessage("defaults: &defaults\n  port: 80\njobs:\n- name: api\n- name: router\n  port: 8080")
+			Expect(message).To(ContainSubstring("error at [jobs][name=router][port]:"))
+			Expect(message).To(ContainSubstring("  location: actual line 6, col 9 / expected line 2, col 9 (inherited from &defaults at line 1)"))
+		})
+
+		It("notes the anchor of values reached through an alias", func() {
+			message := gomegamatchers.HelpfullyMatchYAML("base: &base\n  image: nginx\nweb: *base").FailureMessage("base: &base\n  image: nginx\nweb:\n  image: redis")
+			Expect(message).To(ContainSubstring("error at [web][image]:"))
+			Expect(message).To(ContainSubstring("  location: actual line 4, col 10 / expected line 2, col 10 (inherited from &base at line 1)"))
+		})
+
+		It("does not note an anchor for values defined under it", func() {
+			message := gomegamatchers.HelpfullyMatchYAML("base: &base\n  image: nginx").FailureMessage("base: &base\n  image: redis")
+			Expect(message).To(ContainSubstring("  location: actual line 2, col 10 / expected line 2, col 10"))
+			Expect(message).NotTo(ContainSubstring("inherited"))
+		})
+
+		It("stops listing differences after MaxDifferences", func() {
+			matcher := &gomegamatchers.HelpfullyMatchYAMLMatcher{
+				YAMLToMatch:    "a: 1\nb: 2\nc: 3",
+				MaxDifferences: 2,
+			}
+
+			message := matcher.FailureMessage("a: 0\nb: 0\nc: 0")
+			Expect(message).To(ContainSubstring("error at [a]:"))
+			Expect(message).To(ContainSubstring("error at [b]:"))
+			Expect(message).NotTo(ContainSubstring("error at [c]:"))
+			Expect(message).To(ContainSubstring("stopped after 2 differences"))
+		})
+
+		It("suggests the extra key that a missing key is misspelled as", func() {
+			message := gomegamatchers.HelpfullyMatchYAML("name: web\ninstance_groups: []").FailureMessage("name: web\ninstance_group: []")
+			Expect(message).To(ContainSubstring("\n  missing key 'instance_groups'; did you mean the extra key 'instance_group'?\n"))
+		})
+
+		Context("when a report is requested", func() {
+			var (
+				matcher *gomegamatchers.HelpfullyMatchYAMLMatcher
+				report  *bytes.Buffer
+			)
+
+			BeforeEach(func() {
+				report = &bytes.Buffer{}
+				matcher = &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch:  "defaults: &defaults\n  port: 443\nrouter:\n  <<: *defaults\n  name: router",
+					ReportWriter: report,
+				}
+			})
+
+			It("writes the differences with their positions as JSON", func() {
+				message := matcher.FailureMessage("defaults: &defaults\n  port: 80\nrouter:\n  <<: *defaults\n  name: router")
+				Expect(message).To(ContainSubstring("error at [defaults][port]:"))
+				Expect(report.String()).To(MatchJSON(`{"differences": [
+					{
+						"path": ["defaults", "port"],
+						"kind": "value mismatch",
+						"expected": 443,
+						"actual": 80,
+						"expected_position": {"line": 2, "column": 9},
+						"actual_position": {"line": 2, "column": 9}
+					},
+					{
+						"path": ["router", "port"],
+						"kind": "value mismatch",
+						"expected": 443,
+						"actual": 80,
+						"expected_position": {"line": 2, "column": 9, "anchor": "defaults"},
+						"actual_position": {"line": 2, "column": 9, "anchor": "defaults"}
+					}
+				]}`))
+			})
+
+			It("writes the report to ReportFile", func() {
+				file, err := ioutil.TempFile("", "report-*.json")
+				Expect(err).NotTo(HaveOccurred())
+				defer os.Remove(file.Name())
+				Expect(file.Close()).To(Succeed())
+
+				matcher.ReportWriter = nil
+				matcher.ReportFile = file.Name()
+				matcher.FailureMessage("defaults: {port: 443}\nrouter: {port: 443, name: web}")
+
+				contents, err := ioutil.ReadFile(file.Name())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contents).To(MatchJSON(`{"differences": [{
+					"path": ["router", "name"],
+					"kind": "value mismatch",
+					"expected": "router",
+					"actual": "web",
+					"expected_position": {"line": 5, "column": 9},
+					"actual_position": {"line": 2, "column": 27}
+				}]}`))
+			})
+
+			It("does not write a report when only matching", func() {
+				_, err := matcher.Match("defaults: {port: 80}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Len()).To(BeZero())
+			})
+		})
+
+		Context("when values are large", func() {
+			var limits gomegamatchers.FailureLimits
+
+			BeforeEach(func() {
+				limits = gomegamatchers.TruncateFailures
+			})
+
+			AfterEach(func() {
+				gomegamatchers.TruncateFailures = limits
+			})
+
+			It("prints values in full by default", func() {
+				var properties []string
+				for i := 0; i < 30; i++ {
+					properties = append(properties, fmt.Sprintf("property_%02d: %d", i, i))
+				}
+				actual := strings.Join(append(properties, "instance_group: web"), "\n")
+				expected := strings.Join(append(properties, "instance_groups: web"), "\n")
+
+				message := gomegamatchers.HelpfullyMatchYAML(expected).FailureMessage(actual)
+				Expect(message).To(ContainSubstring("<string> property_29"))
+				Expect(message).NotTo(ContainSubstring(" more]"))
+			})
+
+			It("summarizes the keys past the limit, closest to the missing key first", func() {
+				gomegamatchers.TruncateFailures = gomegamatchers.FailureLimits{MaxElements: 3}
+
+				var properties []string
+				for i := 0; i < 30; i++ {
+					properties = append(properties, fmt.Sprintf("property_%02d: %d", i, i))
+				}
+				actual := strings.Join(append(properties, "instance_group: web"), "\n")
+				expected := strings.Join(append(properties, "instance_groups: web"), "\n")
+
+				message := gomegamatchers.HelpfullyMatchYAML(expected).FailureMessage(actual)
+				Expect(message).To(ContainSubstring("[<string> instance_group, <string> property_00, <string> property_01, ... 28 more]"))
+			})
+
+			It("cuts long strings down to the limit", func() {
+				gomegamatchers.TruncateFailures = gomegamatchers.FailureLimits{MaxStringLength: 10}
+
+				message := gomegamatchers.HelpfullyMatchYAML("banner: welcome to the production environment").
+					FailureMessage("banner: welcome to the staging environment")
+				Expect(message).To(ContainSubstring("<string> welcome to... 24 more\n"))
+				Expect(message).To(ContainSubstring("<string> welcome to... 27 more\n"))
+				Expect(message).To(ContainSubstring("first difference at offset 15 (line 1, col 16)"))
+			})
+		})
+
+		It("locates the difference inside multi-line string values", func() {
+			expected := "script: |\n  #!/bin/bash\n  set -e\n  exec server --port 8080\n"
+			actual := "script: |\n  #!/bin/bash\n  set -e\n  exec server --port 8081\n"
+
+			message := gomegamatchers.HelpfullyMatchYAML(expected).FailureMessage(actual)
+			Expect(message).To(ContainSubstring("error at [script]:\n  value mismatch:"))
+			Expect(message).To(ContainSubstring("    first difference at offset 41 (line 3, col 23):\n        -exec server --port 8080\n        +exec server --port 8081\n  location: actual line 1, col 9 / expected line 1, col 9"))
+		})
+
+		Context("when a unified diff is requested", func() {
+			var matcher *gomegamatchers.HelpfullyMatchYAMLMatcher
+
+			BeforeEach(func() {
+				matcher = &gomegamatchers.HelpfullyMatchYAMLMatcher{
+					YAMLToMatch: "a: 1\nb: 2\nc: 3",
+					DiffContext: 1,
+				}
+			})
+
+			It("shows only the unified diff of the normalized YAML", func() {
+				matcher.FailureFormat = gomegamatchers.UnifiedDiff
+
+				message := matcher.FailureMessage("c: 3\nb: 0\na: 1")
+				Expect(message).NotTo(ContainSubstring("error at"))
+				Expect(message).To(Equal("unified diff (- expected, + actual):\n--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n a: 1\n-b: 2\n+b: 0\n c: 3"))
+			})
+
+			It("shows the unified diff after the localized failures", func() {
+				matcher.FailureFormat = gomegamatchers.LocalizedFailuresAndUnifiedDiff
+
+				message := matcher.FailureMessage("a: 1\nb: 0\nc: 3")
+				Expect(message).To(ContainSubstring("error at [b]:\n  value mismatch:"))
+				Expect(message).To(ContainSubstring("\n\nunified diff (- expected, + actual):\n"))
+				Expect(message).To(ContainSubstring("-b: 2\n+b: 0"))
+			})
+
+			It("builds the unified diff only for the failure message", func() {
+				matcher.FailureFormat = gomegamatchers.LocalizedFailuresAndUnifiedDiff
+				matcher.YAMLToMatch = map[string]interface{}{
+					"a": WithTransform(func(s string) int { return len(s) }, Equal(1)),
+				}
+
+				isMatch, err := matcher.Match("a: xy")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isMatch).To(BeFalse())
+
+				message := matcher.FailureMessage("a: xy")
+				Expect(message).To(ContainSubstring("error at [a]:\n  matcher failed:"))
+				Expect(message).To(ContainSubstring("-a: <*matchers.WithTransformMatcher>\n+a: xy"))
+			})
+		})
+
 		Describe("errors", func() {
 			It("returns the error as the message", func() {
 				message := gomegamatchers.HelpfullyMatchYAML(animals).FailureMessage("some: invalid: yaml")
@@ -176,5 +1106,50 @@ var _ = Describe("HelpfullyMatchYAMLMatcher", func() {
 			Expect(message).To(ContainSubstring("not to match YAML of"))
 			Expect(message).To(ContainSubstring("<string>: a: 1"))
 		})
+
+		It("prints embedded matchers by their type", func() {
+			matcher := gomegamatchers.HelpfullyMatchYAML(map[string]interface{}{
+				"a": WithTransform(func(s string) int { return len(s) }, Equal(1)),
+				"b": MatchRegexp("^x"),
+			})
+
+			message := matcher.NegatedFailureMessage("a: x\nb: x")
+			Expect(message).To(ContainSubstring("not to match YAML of"))
+			Expect(message).To(ContainSubstring("a: <*matchers.WithTransformMatcher>"))
+			Expect(message).To(ContainSubstring("b: <*matchers.MatchRegexpMatcher>"))
+			Expect(message).NotTo(ContainSubstring("regexp:"))
+		})
+	})
+
+	Describe("HelpfullyContainYAML", func() {
+		var manifest string
+
+		BeforeEach(func() {
+			manifest = "name: cf\nreleases:\n- name: cf\n  version: 1\nproperties:\n  router:\n    port: 80\n    ssl: true"
+		})
+
+		It("succeeds when actual contains the expected YAML", func() {
+			isMatch, err := gomegamatchers.HelpfullyContainYAML("properties:\n  router:\n    port: 80").Match(manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isMatch).To(BeTrue())
+		})
+
+		It("fails with localized differences for missing keys and mismatched values", func() {
+			matcher := gomegamatchers.HelpfullyContainYAML("name: diego\nproperties:\n  router:\n    timeout: 5")
+
+			isMatch, err := matcher.Match(manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isMatch).To(BeFalse())
+
+			message := matcher.FailureMessage(manifest)
+			Expect(message).To(ContainSubstring("error at [name]:\n  value mismatch:"))
+			Expect(message).To(ContainSubstring("error at [properties][router]:\n  missing key:"))
+			Expect(message).NotTo(ContainSubstring("extra key found"))
+		})
+
+		It("returns a negated failure message", func() {
+			message := gomegamatchers.HelpfullyContainYAML("a: 1").NegatedFailureMessage("a: 1\nb: 2")
+			Expect(message).To(ContainSubstring("not to contain YAML of"))
+		})
 	})
 })
@@ -0,0 +1,36 @@
+package gomegamatchers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// writeReport writes report as indented JSON to ReportFile and
+// ReportWriter, whichever are set.
+func (matcher *HelpfullyMatchYAMLMatcher) writeReport(report diff.Report) error {
+	if matcher.ReportFile == "" && matcher.ReportWriter == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if matcher.ReportFile != "" {
+		if err := ioutil.WriteFile(matcher.ReportFile, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	if matcher.ReportWriter != nil {
+		if _, err := matcher.ReportWriter.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
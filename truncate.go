@@ -0,0 +1,44 @@
+package gomegamatchers
+
+import (
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/prettyprint"
+)
+
+// FailureLimits bound how much of each value the failure messages of the
+// Helpfully matchers print. A zero limit is no limit.
+type FailureLimits struct {
+	// MaxElements caps the elements of sequences and maps, the keys listed
+	// next to a missing or extra key, and the changed lines of multi-line
+	// strings. The rest are summarized as "... N more", and the keys
+	// closest to the missing or extra key are the ones listed.
+	MaxElements int
+
+	// MaxStringLength caps the characters printed of each string.
+	MaxStringLength int
+
+	// MaxDepth caps how deeply sequences and maps nested within a value are
+	// printed.
+	MaxDepth int
+}
+
+// TruncateFailures is applied to every failure message. By default values
+// are printed in full.
+var TruncateFailures FailureLimits
+
+// failureStyle is the style of failure messages, as set by ColorFailures
+// and TruncateFailures.
+func failureStyle() diff.Style {
+	style := diff.Plain
+	if colorFailures() {
+		style = prettyprint.Colors
+	}
+
+	style.Limits = diff.Limits{
+		MaxElements:     TruncateFailures.MaxElements,
+		MaxStringLength: TruncateFailures.MaxStringLength,
+		MaxDepth:        TruncateFailures.MaxDepth,
+	}
+
+	return style
+}
@@ -0,0 +1,118 @@
+package gomegamatchers
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// yamlAlias stands for an alias that was left unexpanded. Two aliases are
+// equal when they name the same anchor.
+type yamlAlias string
+
+func (alias yamlAlias) String() string {
+	return "*" + string(alias)
+}
+
+func (alias yamlAlias) MarshalYAML() (interface{}, error) {
+	return alias.String(), nil
+}
+
+// unmarshalPreservingAliases decodes every document of a YAML stream the way
+// yaml.v2 does, except that aliases, including those of "<<" merge keys,
+// are kept as yamlAlias values instead of being expanded.
+func unmarshalPreservingAliases(input string) ([]interface{}, error) {
+	var documents []interface{}
+
+	decoder := yaml3.NewDecoder(strings.NewReader(input))
+	for {
+		var document yaml3.Node
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := nodeValue(&document)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, value)
+	}
+
+	if len(documents) == 0 {
+		documents = []interface{}{nil}
+	}
+
+	return withoutEmptyDocuments(documents), nil
+}
+
+func nodeValue(node *yaml3.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml3.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+
+		return nodeValue(node.Content[0])
+
+	case yaml3.AliasNode:
+		return yamlAlias(node.Value), nil
+
+	case yaml3.MappingNode:
+		mapping := map[interface{}]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, err := nodeValue(node.Content[i])
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := nodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			mapping[key] = value
+		}
+		return mapping, nil
+
+	case yaml3.SequenceNode:
+		sequence := []interface{}{}
+		for _, element := range node.Content {
+			value, err := nodeValue(element)
+			if err != nil {
+				return nil, err
+			}
+
+			sequence = append(sequence, value)
+		}
+		return sequence, nil
+
+	default:
+		if node.Tag == "!!merge" {
+			return node.Value, nil
+		}
+
+		return scalarValue(node)
+	}
+}
+
+// scalarValue resolves a scalar node to the value yaml.v2 decodes it to.
+// yaml.v3 resolves scalars such as yes, on and 0777 by YAML 1.2 rules, so
+// the scalar is written back out with its style and any explicit tag, and
+// resolved by yaml.v2 like the rest of the input.
+func scalarValue(node *yaml3.Node) (interface{}, error) {
+	text, err := yaml3.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	err = yaml.Unmarshal(text, &value)
+	return value, err
+}
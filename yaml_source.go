@@ -0,0 +1,303 @@
+package gomegamatchers
+
+import (
+	"fmt"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// yamlSource keeps the node trees of the documents of a YAML input so that
+// differences can be traced back to the line and column they came from.
+type yamlSource struct {
+	roots    []*yaml3.Node
+	file     string
+	expected bool
+}
+
+// newYAMLSource parses the node trees of input, which was read from file
+// when file is not empty.
+func newYAMLSource(input interface{}, file string, expected bool) *yamlSource {
+	inputString, ok := toString(input)
+	if !ok {
+		return nil
+	}
+
+	source := &yamlSource{file: file, expected: expected}
+
+	decoder := yaml3.NewDecoder(strings.NewReader(inputString))
+	for {
+		var document yaml3.Node
+		if err := decoder.Decode(&document); err != nil {
+			break
+		}
+
+		if len(document.Content) > 0 && document.Content[0].ShortTag() != "!!null" {
+			source.roots = append(source.roots, document.Content[0])
+		}
+	}
+
+	if len(source.roots) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+// find returns the node at path, or nil when the path does not exist. With
+// key set and a path that ends in a map key, it returns the node of the key
+// rather than of its value. When the path passes through an alias or a "<<"
+// merge key, find also returns the anchored node that the value was
+// inherited from.
+func (source *yamlSource) find(path []interface{}, key bool) (*yaml3.Node, *yaml3.Node) {
+	if source == nil {
+		return nil, nil
+	}
+
+	node := source.roots[0]
+	if len(path) > 0 {
+		if document, ok := path[0].(diff.DocumentKey); ok {
+			index := document.ActualIndex
+			if source.expected {
+				index = document.ExpectedIndex
+			}
+
+			if index < 0 || index >= len(source.roots) {
+				return nil, nil
+			}
+
+			node, path = source.roots[index], path[1:]
+		}
+	}
+
+	var anchor *yaml3.Node
+	for i, segment := range path {
+		parent := resolveAlias(node)
+		if parent != node {
+			anchor = parent
+		}
+
+		if key && i == len(path)-1 && parent.Kind == yaml3.MappingNode {
+			found := mappingKey(parent, segment)
+			if found == nil {
+				return nil, nil
+			}
+			if found.anchor != nil {
+				anchor = found.anchor
+			}
+
+			return found.Node, anchor
+		}
+
+		var inherited *yaml3.Node
+		node, inherited = child(parent, segment)
+		if node == nil {
+			return nil, nil
+		}
+		if inherited != nil {
+			anchor = inherited
+		}
+	}
+
+	if resolved := resolveAlias(node); resolved != node {
+		anchor = resolved
+	}
+
+	return node, anchor
+}
+
+// child returns the node of a map value or slice element, along with the
+// anchored mapping it was merged from, if any.
+func child(node *yaml3.Node, segment interface{}) (*yaml3.Node, *yaml3.Node) {
+	switch node.Kind {
+	case yaml3.MappingNode:
+		if key := mappingKey(node, segment); key != nil {
+			return key.value, key.anchor
+		}
+
+	case yaml3.SequenceNode:
+		switch segment := segment.(type) {
+		case int:
+			if segment < len(node.Content) {
+				return node.Content[segment], nil
+			}
+
+		case diff.ElementKey:
+			for _, element := range node.Content {
+				if key := mappingKey(resolveAlias(element), segment.Field); key != nil && scalarIs(key.value, segment.Value) {
+					return element, nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// mappingKey looks up key, a value decoded by yaml.v2, in a mapping node,
+// including the mappings that it pulls in through "<<" merge keys.
+func mappingKey(node *yaml3.Node, key interface{}) *keyNode {
+	if node.Kind != yaml3.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag != "!!merge" && scalarIs(node.Content[i], key) {
+			return &keyNode{Node: node.Content[i], value: node.Content[i+1]}
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag != "!!merge" {
+			continue
+		}
+
+		merged := resolveAlias(node.Content[i+1])
+		sources := []*yaml3.Node{merged}
+		if merged.Kind == yaml3.SequenceNode {
+			sources = merged.Content
+		}
+
+		for _, source := range sources {
+			resolved := resolveAlias(source)
+			if found := mappingKey(resolved, key); found != nil {
+				if found.anchor == nil && resolved.Anchor != "" {
+					found.anchor = resolved
+				}
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+// keyNode is the node of a mapping key, together with the node of its value
+// and the anchored mapping it was merged from, if any.
+type keyNode struct {
+	*yaml3.Node
+	value  *yaml3.Node
+	anchor *yaml3.Node
+}
+
+// scalarIs reports whether node is a scalar that yaml.v2 decodes to value,
+// so that keys such as on and 01 are found by the true and 1 they decode to.
+func scalarIs(node *yaml3.Node, value interface{}) bool {
+	node = resolveAlias(node)
+	if node.Kind != yaml3.ScalarNode {
+		return false
+	}
+
+	resolved, err := scalarValue(node)
+	return err == nil && resolved == value
+}
+
+func resolveAlias(node *yaml3.Node) *yaml3.Node {
+	for node.Kind == yaml3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// locateDifferences describes where each difference appears in the actual
+// and expected sources.
+func locateDifferences(expected *yamlSource, actual *yamlSource) func(diff.Difference) string {
+	return func(difference diff.Difference) string {
+		expectedNode, expectedAnchor, actualNode, actualAnchor := sourceNodes(expected, actual, difference)
+
+		var locations []string
+		if actualNode != nil {
+			locations = append(locations, "actual "+actual.location(actualNode, actualAnchor))
+		}
+		if expectedNode != nil {
+			locations = append(locations, "expected "+expected.location(expectedNode, expectedAnchor))
+		}
+
+		if len(locations) == 0 {
+			return ""
+		}
+
+		return "  location: " + strings.Join(locations, " / ")
+	}
+}
+
+// positionDifferences returns the positions of each difference in the
+// expected and actual sources, for reports.
+func positionDifferences(expected *yamlSource, actual *yamlSource) func(diff.Difference) (*diff.Position, *diff.Position) {
+	return func(difference diff.Difference) (*diff.Position, *diff.Position) {
+		expectedNode, expectedAnchor, actualNode, actualAnchor := sourceNodes(expected, actual, difference)
+
+		return expected.position(expectedNode, expectedAnchor), actual.position(actualNode, actualAnchor)
+	}
+}
+
+// sourceNodes finds the nodes of the expected and actual sides of a
+// difference, and the anchors they were inherited from. Either node is nil
+// when that side has no value at the path.
+func sourceNodes(expected *yamlSource, actual *yamlSource, difference diff.Difference) (*yaml3.Node, *yaml3.Node, *yaml3.Node, *yaml3.Node) {
+	path, leaf := diff.Unwrap(difference)
+	actualPath, expectedPath := path, path
+	actualKey, expectedKey := false, false
+
+	switch leaf := leaf.(type) {
+	case diff.MapExtraKey:
+		actualPath, actualKey = appendSegment(path, leaf.ExtraKey), true
+	case diff.MapMissingKey:
+		expectedPath, expectedKey = appendSegment(path, leaf.MissingKey), true
+	case diff.SliceExtraKeyedElement:
+		actualPath = appendSegment(path, leaf.ExtraKey)
+	case diff.SliceMissingKeyedElement:
+		expectedPath = appendSegment(path, leaf.MissingKey)
+	case diff.DocumentExtra:
+		// The key of a document on one side only has an index of -1 on the
+		// other, where find then finds nothing.
+		actualPath = appendSegment(path, leaf.ExtraDocument)
+		expectedPath = actualPath
+	case diff.DocumentMissing:
+		expectedPath = appendSegment(path, leaf.MissingDocument)
+		actualPath = expectedPath
+	}
+
+	expectedNode, expectedAnchor := expected.find(expectedPath, expectedKey)
+	actualNode, actualAnchor := actual.find(actualPath, actualKey)
+
+	return expectedNode, expectedAnchor, actualNode, actualAnchor
+}
+
+// location prints the line and column of a node, the file it is in, and the
+// anchor it was inherited from.
+func (source *yamlSource) location(node *yaml3.Node, anchor *yaml3.Node) string {
+	text := fmt.Sprintf("line %d, col %d", node.Line, node.Column)
+	if source.file != "" {
+		text += " in " + source.file
+	}
+	if anchor != nil && anchor.Anchor != "" {
+		text += fmt.Sprintf(" (inherited from &%s at line %d)", anchor.Anchor, anchor.Line)
+	}
+
+	return text
+}
+
+// position is the report form of location, or nil without a node.
+func (source *yamlSource) position(node *yaml3.Node, anchor *yaml3.Node) *diff.Position {
+	if node == nil {
+		return nil
+	}
+
+	position := &diff.Position{File: source.file, Line: node.Line, Column: node.Column}
+	if anchor != nil {
+		position.Anchor = anchor.Anchor
+	}
+
+	return position
+}
+
+func appendSegment(path []interface{}, segment interface{}) []interface{} {
+	extended := make([]interface{}, len(path), len(path)+1)
+	copy(extended, path)
+
+	return append(extended, segment)
+}
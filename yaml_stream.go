@@ -0,0 +1,174 @@
+package gomegamatchers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/deepequal"
+	"github.com/pivotal-cf-experimental/gomegamatchers/internal/diff"
+)
+
+// compareStreams compares the documents of two YAML streams, pairing them by
+// position or, when DocumentKeys is set, by identity. options.MaxDifferences
+// applies to the whole stream.
+func (matcher *HelpfullyMatchYAMLMatcher) compareStreams(expected []interface{}, actual []interface{}, options deepequal.Options) ([]diff.Difference, error) {
+	expectedKeys, err := matcher.documentKeys(expected, true)
+	if err != nil {
+		return nil, err
+	}
+
+	actualKeys, err := matcher.documentKeys(actual, false)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedByName := map[string]diff.DocumentKey{}
+	for _, key := range expectedKeys {
+		expectedByName[key.Name] = key
+	}
+
+	actualByName := map[string]diff.DocumentKey{}
+	for _, key := range actualKeys {
+		actualByName[key.Name] = key
+	}
+
+	var differences []diff.Difference
+	full := func() bool {
+		return options.MaxDifferences > 0 && len(differences) >= options.MaxDifferences
+	}
+
+	for _, actualKey := range actualKeys {
+		if full() {
+			return differences, nil
+		}
+
+		expectedKey, found := expectedByName[actualKey.Name]
+		if !found {
+			differences = append(differences, diff.DocumentExtra{
+				ExtraDocument: actualKey,
+				AllDocuments:  actualKeys,
+			})
+			continue
+		}
+
+		key := diff.DocumentKey{
+			Name:          actualKey.Name,
+			ExpectedIndex: expectedKey.ExpectedIndex,
+			ActualIndex:   actualKey.ActualIndex,
+		}
+
+		documentOptions := options
+		if options.MaxDifferences > 0 {
+			documentOptions.MaxDifferences = options.MaxDifferences - len(differences)
+		}
+		if options.OnLooseMatch != nil {
+			documentOptions.OnLooseMatch = func(looseMatch diff.Difference) {
+				options.OnLooseMatch(diff.DocumentNested{
+					Document:         key,
+					NestedDifference: looseMatch,
+				})
+			}
+		}
+
+		nested := deepequal.CompareAll(expected[key.ExpectedIndex], actual[key.ActualIndex], documentOptions)
+		for _, difference := range nested {
+			differences = append(differences, diff.DocumentNested{
+				Document:         key,
+				NestedDifference: difference,
+			})
+		}
+	}
+
+	for _, expectedKey := range expectedKeys {
+		if full() {
+			return differences, nil
+		}
+
+		if _, found := actualByName[expectedKey.Name]; !found {
+			differences = append(differences, diff.DocumentMissing{
+				MissingDocument: expectedKey,
+				AllDocuments:    actualKeys,
+			})
+		}
+	}
+
+	return differences, nil
+}
+
+// documentKeys names each document by its index, or by the values at
+// DocumentKeys when it is set.
+func (matcher *HelpfullyMatchYAMLMatcher) documentKeys(documents []interface{}, expected bool) ([]diff.DocumentKey, error) {
+	patterns, err := deepequal.ParsePatterns(matcher.DocumentKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []diff.DocumentKey
+	names := map[string]bool{}
+
+	for i, document := range documents {
+		name := strconv.Itoa(i)
+		if len(patterns) > 0 {
+			name, err = documentName(document, matcher.DocumentKeys, patterns)
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %s", i, err)
+			}
+		}
+
+		if names[name] {
+			return nil, fmt.Errorf("document %d: another document is also identified by %s", i, name)
+		}
+		names[name] = true
+
+		key := diff.DocumentKey{Name: name, ExpectedIndex: -1, ActualIndex: -1}
+		if expected {
+			key.ExpectedIndex = i
+		} else {
+			key.ActualIndex = i
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func documentName(document interface{}, fields []string, patterns []deepequal.Pattern) (string, error) {
+	var parts []string
+	for i, pattern := range patterns {
+		value, ok := lookup(document, pattern)
+		if !ok {
+			return "", fmt.Errorf("no value at %s to identify it", fields[i])
+		}
+
+		parts = append(parts, fmt.Sprintf("%s=%+v", fields[i], value))
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// lookup follows the map keys of pattern into document.
+func lookup(document interface{}, pattern deepequal.Pattern) (interface{}, bool) {
+	value := document
+	for _, segment := range pattern {
+		mapping, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		found := false
+		for key, child := range mapping {
+			if fmt.Sprintf("%+v", key) == segment {
+				value, found = child, true
+				break
+			}
+		}
+
+		if !found {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,78 @@
+package gomegamatchers
+
+import (
+	"fmt"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// checkKeys returns an error locating, with duplicates, the first duplicate
+// map key of a YAML input, and with stringKeysOnly, the first key that is
+// not a string. The side names the input in the error, as in "actual YAML".
+func checkKeys(input interface{}, side string, duplicates bool, stringKeysOnly bool) error {
+	inputString, ok := toString(input)
+	if !ok {
+		return nil
+	}
+
+	decoder := yaml3.NewDecoder(strings.NewReader(inputString))
+	for {
+		var document yaml3.Node
+		if err := decoder.Decode(&document); err != nil {
+			return nil
+		}
+
+		if err := checkNodeKeys(&document, side, duplicates, stringKeysOnly); err != nil {
+			return err
+		}
+	}
+}
+
+func checkNodeKeys(node *yaml3.Node, side string, duplicates bool, stringKeysOnly bool) error {
+	if node.Kind == yaml3.MappingNode {
+		seen := map[interface{}]*yaml3.Node{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Tag == "!!merge" {
+				continue
+			}
+
+			// Keys are resolved the way yaml.v2 decodes them, so that on and
+			// true, or 1 and 01, are the same key.
+			resolved := resolveAlias(key)
+			if resolved.Kind != yaml3.ScalarNode {
+				if stringKeysOnly {
+					return fmt.Errorf("non-string key (%s) in %s at line %d, col %d", resolved.ShortTag(), side, key.Line, key.Column)
+				}
+				continue
+			}
+
+			value, err := scalarValue(resolved)
+			if err != nil {
+				return err
+			}
+
+			if _, isString := value.(string); stringKeysOnly && !isString {
+				return fmt.Errorf("non-string key %s (%T) in %s at line %d, col %d", resolved.Value, value, side, key.Line, key.Column)
+			}
+
+			if !duplicates {
+				continue
+			}
+
+			if first, found := seen[value]; found {
+				return fmt.Errorf("duplicate key %q in %s at line %d, col %d, first defined at line %d, col %d", key.Value, side, key.Line, key.Column, first.Line, first.Column)
+			}
+			seen[value] = key
+		}
+	}
+
+	for _, child := range node.Content {
+		if err := checkNodeKeys(child, side, duplicates, stringKeysOnly); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}